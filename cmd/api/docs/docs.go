@@ -15,50 +15,88 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/subscription/confirm/{token}": {
+        "/alerts": {
             "get": {
-                "description": "Confirms a subscription using the token from the confirmation email.",
+                "description": "Returns active government weather alerts with headline, severity and effective/expiry times.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "subscription"
+                    "weather"
                 ],
-                "summary": "Confirm subscription",
+                "summary": "Get severe weather alerts for a city",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Confirmation token",
-                        "name": "token",
-                        "in": "path",
-                        "required": true
+                        "description": "City name, required unless lat and lon are given",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Latitude, -90 to 90",
+                        "name": "lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude, -180 to 180",
+                        "name": "lon",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Subscription confirmed successfully",
+                        "description": "Active alerts returned",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/model.Alerts"
                         }
                     },
                     "400": {
-                        "description": "Invalid token",
+                        "description": "Invalid request",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Token not found",
+                        "description": "City not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "501": {
+                        "description": "Not supported by the weather provider",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Weather provider unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Weather provider quota exceeded or temporarily unavailable",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the weather provider is retried"
+                            }
                         }
                     }
                 }
             }
         },
-        "/subscription/subscribe": {
-            "post": {
-                "description": "Subscribes an email to weather updates for a city with a frequency.",
+        "/astronomy": {
+            "get": {
+                "description": "Returns sunrise, sunset, moonrise, moonset and moon phase for a date. When the weather provider is unavailable and the location is given as coordinates, sun times and moon phase are calculated locally.",
                 "consumes": [
                     "application/json"
                 ],
@@ -66,92 +104,159 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscription"
+                    "weather"
                 ],
-                "summary": "Subscribe to weather updates",
+                "summary": "Get sunrise, sunset and moon data for a city",
                 "parameters": [
                     {
-                        "description": "Subscription request",
-                        "name": "subscription",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.Subscription"
-                        }
+                        "type": "string",
+                        "description": "City name, required unless lat and lon are given",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Latitude, -90 to 90",
+                        "name": "lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude, -180 to 180",
+                        "name": "lon",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date in YYYY-MM-DD format, defaults to today (UTC)",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "uk"
+                        ],
+                        "type": "string",
+                        "description": "Language of descriptions, overrides Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred language of descriptions",
+                        "name": "Accept-Language",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Subscription request accepted. Confirmation email sent.",
+                        "description": "Astronomy data returned",
                         "schema": {
-                            "$ref": "#/definitions/model.Subscription"
+                            "$ref": "#/definitions/model.Astronomy"
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Invalid request",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Email already subscribed",
+                    "404": {
+                        "description": "City not found",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal error",
+                    "501": {
+                        "description": "Not supported by the weather provider",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Weather provider unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Weather provider quota exceeded or temporarily unavailable",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the weather provider is retried"
+                            }
                         }
                     }
                 }
             }
         },
-        "/subscription/unsubscribe/{token}": {
+        "/cities/search": {
             "get": {
-                "description": "Unsubscribes an email using the provided token.",
+                "description": "Returns locations matching a partial city name, best matches first, with a stable location ID. Used for autocomplete.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "subscription"
+                    "locations"
                 ],
-                "summary": "Unsubscribe from weather updates",
+                "summary": "Search cities",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Unsubscribe token",
-                        "name": "token",
-                        "in": "path",
+                        "description": "Partial city name (2-100 characters)",
+                        "name": "q",
+                        "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Unsubscribed successfully",
+                        "description": "Matching locations, possibly empty",
                         "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Location"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid token",
+                        "description": "Invalid request",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Token not found",
+                    "502": {
+                        "description": "Weather provider unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Weather provider quota exceeded or temporarily unavailable",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the weather provider is retried"
+                            }
                         }
                     }
                 }
             }
         },
-        "/weather": {
+        "/forecast": {
             "get": {
-                "description": "Returns the current weather for the specified city using WeatherAPI.com.",
+                "description": "Returns daily min/max temperature, chance of rain, condition and max wind speed for up to 14 days.",
                 "consumes": [
                     "application/json"
                 ],
@@ -161,21 +266,67 @@ const docTemplate = `{
                 "tags": [
                     "weather"
                 ],
-                "summary": "Get current weather for a city",
+                "summary": "Get daily forecast for a city",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "City name",
+                        "description": "City name, required unless lat and lon are given",
                         "name": "city",
-                        "in": "query",
-                        "required": true
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Latitude, -90 to 90",
+                        "name": "lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude, -180 to 180",
+                        "name": "lon",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 3,
+                        "description": "Number of days (1-14)",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "metric",
+                            "imperial",
+                            "scientific"
+                        ],
+                        "type": "string",
+                        "default": "metric",
+                        "description": "Unit system",
+                        "name": "units",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "uk"
+                        ],
+                        "type": "string",
+                        "description": "Language of descriptions, overrides Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred language of descriptions",
+                        "name": "Accept-Language",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Current weather returned",
+                        "description": "Forecast returned",
                         "schema": {
-                            "$ref": "#/definitions/model.Weather"
+                            "$ref": "#/definitions/model.Forecast"
                         }
                     },
                     "400": {
@@ -189,63 +340,1244 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
+                    },
+                    "502": {
+                        "description": "Weather provider unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Weather provider quota exceeded or temporarily unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the weather provider is retried"
+                            }
+                        }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "model.Subscription": {
-            "type": "object",
-            "properties": {
-                "city": {
-                    "type": "string"
-                },
-                "confirmed": {
-                    "type": "boolean"
-                },
-                "email": {
-                    "type": "string"
-                },
-                "frequency": {
-                    "type": "string"
-                },
-                "token": {
-                    "type": "string"
-                }
-            }
-        },
-        "model.Weather": {
-            "type": "object",
-            "properties": {
-                "description": {
-                    "type": "string"
-                },
-                "humidity": {
-                    "type": "number"
-                },
-                "temperature": {
-                    "type": "number"
-                }
-            }
         },
-        "response.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "type": "string"
+        "/forecast/hourly": {
+            "get": {
+                "description": "Returns hourly temperature, precipitation and condition points between from and to (at most 48 hours). Defaults to the next 24 hours.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "weather"
+                ],
+                "summary": "Get hourly forecast for a city",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "City name, required unless lat and lon are given",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Latitude, -90 to 90",
+                        "name": "lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude, -180 to 180",
+                        "name": "lon",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Window start, RFC3339 (default: now)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Window end, RFC3339 (default: from + 24h)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "metric",
+                            "imperial",
+                            "scientific"
+                        ],
+                        "type": "string",
+                        "default": "metric",
+                        "description": "Unit system",
+                        "name": "units",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "uk"
+                        ],
+                        "type": "string",
+                        "description": "Language of descriptions, overrides Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred language of descriptions",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Hourly forecast returned",
+                        "schema": {
+                            "$ref": "#/definitions/model.HourlyForecast"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "City not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Weather provider unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Weather provider quota exceeded or temporarily unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the weather provider is retried"
+                            }
+                        }
+                    }
                 }
             }
-        }
-    },
-    "tags": [
-        {
-            "description": "Weather forecast operations",
-            "name": "weather"
         },
-        {
-            "description": "Subscription management operations",
-            "name": "subscription"
+        "/history": {
+            "get": {
+                "description": "Returns observed conditions for a past date, from locally stored observations when available or from the weather provider's history API.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "weather"
+                ],
+                "summary": "Get historical weather for a city",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "City name, required unless lat and lon are given",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Latitude, -90 to 90",
+                        "name": "lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude, -180 to 180",
+                        "name": "lon",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Past date in YYYY-MM-DD format",
+                        "name": "date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "metric",
+                            "imperial",
+                            "scientific"
+                        ],
+                        "type": "string",
+                        "default": "metric",
+                        "description": "Unit system",
+                        "name": "units",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "uk"
+                        ],
+                        "type": "string",
+                        "description": "Language of descriptions, overrides Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred language of descriptions",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Historical weather returned",
+                        "schema": {
+                            "$ref": "#/definitions/model.History"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "City or data not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Weather provider unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Weather provider quota exceeded or temporarily unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the weather provider is retried"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/status/providers": {
+            "get": {
+                "description": "Returns the circuit breaker of each upstream weather provider: closed (healthy), open (calls rejected until retry_after_seconds pass) or half-open (a probe call is deciding).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "status"
+                ],
+                "summary": "Get weather provider circuit breaker states",
+                "responses": {
+                    "200": {
+                        "description": "Circuit breaker states, in provider priority order",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/resilience.Status"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/status/usage": {
+            "get": {
+                "description": "Returns the outbound calls made to each configured weather provider in the current UTC day and month, with the configured budgets.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "status"
+                ],
+                "summary": "Get weather provider API consumption",
+                "responses": {
+                    "200": {
+                        "description": "Usage per provider, in priority order",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ProviderUsage"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscription/confirm/{token}": {
+            "get": {
+                "description": "Confirms a subscription using the token from the confirmation email.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Confirm subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Confirmation token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Subscription confirmed successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Token not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscription/subscribe": {
+            "post": {
+                "description": "Subscribes an email to weather updates for a city, or for a latitude/longitude pair, with a frequency. The \"alerts\" frequency only sends emails when a new severe weather alert is issued.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Subscribe to weather updates",
+                "parameters": [
+                    {
+                        "description": "Subscription request",
+                        "name": "subscription",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Subscription"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Email language when the request has none",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Subscription request accepted. Confirmation email sent.",
+                        "schema": {
+                            "$ref": "#/definitions/model.Subscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input or city not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Email already subscribed",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscription/unsubscribe/{token}": {
+            "get": {
+                "description": "Unsubscribes an email using the provided token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Unsubscribe from weather updates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unsubscribe token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Unsubscribed successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Token not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/weather": {
+            "get": {
+                "description": "Returns the current weather for the specified city or coordinates using the configured weather provider.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "weather"
+                ],
+                "summary": "Get current weather for a city",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "City name, required unless lat and lon are given",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Latitude, -90 to 90",
+                        "name": "lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude, -180 to 180",
+                        "name": "lon",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "aqi"
+                        ],
+                        "type": "string",
+                        "description": "Comma-separated extra data to include",
+                        "name": "include",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated response fields to return, e.g. temperature,wind_speed,uv_index (units is always returned)",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "metric",
+                            "imperial",
+                            "scientific"
+                        ],
+                        "type": "string",
+                        "default": "metric",
+                        "description": "Unit system",
+                        "name": "units",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "uk"
+                        ],
+                        "type": "string",
+                        "description": "Language of descriptions, overrides Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred language of descriptions",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Current weather returned",
+                        "schema": {
+                            "$ref": "#/definitions/model.Weather"
+                        },
+                        "headers": {
+                            "Age": {
+                                "type": "integer",
+                                "description": "Age of the cached response in seconds"
+                            },
+                            "X-Cache": {
+                                "type": "string",
+                                "description": "HIT, MISS or STALE"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "City not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Weather provider unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Weather provider quota exceeded or temporarily unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the weather provider is retried"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/weather/batch": {
+            "post": {
+                "description": "Returns the current weather for up to WEATHER_BATCH_MAX_ITEMS cities or coordinates, fetched concurrently.\nResults are in request order; each has the status and error GET /weather would return for that location.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "weather"
+                ],
+                "summary": "Get current weather for several locations",
+                "parameters": [
+                    {
+                        "description": "Locations to look up",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.BatchWeatherRequest"
+                        }
+                    },
+                    {
+                        "enum": [
+                            "metric",
+                            "imperial",
+                            "scientific"
+                        ],
+                        "type": "string",
+                        "default": "metric",
+                        "description": "Unit system",
+                        "name": "units",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "uk"
+                        ],
+                        "type": "string",
+                        "description": "Language of descriptions, overrides Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred language of descriptions",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Per-location results returned",
+                        "schema": {
+                            "$ref": "#/definitions/model.BatchWeatherResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "model.AirQuality": {
+            "type": "object",
+            "properties": {
+                "gb_defra_category": {
+                    "type": "string",
+                    "example": "Low"
+                },
+                "gb_defra_index": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "no2": {
+                    "type": "number"
+                },
+                "o3": {
+                    "type": "number"
+                },
+                "pm10": {
+                    "type": "number"
+                },
+                "pm2_5": {
+                    "type": "number"
+                },
+                "us_epa_category": {
+                    "type": "string",
+                    "example": "Moderate"
+                },
+                "us_epa_index": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "model.Alert": {
+            "type": "object",
+            "properties": {
+                "areas": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "effective": {
+                    "type": "string"
+                },
+                "event": {
+                    "type": "string"
+                },
+                "expires": {
+                    "type": "string"
+                },
+                "headline": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "severity": {
+                    "type": "string",
+                    "example": "Moderate"
+                }
+            }
+        },
+        "model.Alerts": {
+            "type": "object",
+            "properties": {
+                "alerts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Alert"
+                    }
+                },
+                "city": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Astronomy": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2025-06-01"
+                },
+                "moon_illumination": {
+                    "type": "number"
+                },
+                "moon_phase": {
+                    "type": "string",
+                    "example": "Waxing Crescent"
+                },
+                "moonrise": {
+                    "type": "string"
+                },
+                "moonset": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string",
+                    "enum": [
+                        "provider",
+                        "calculated"
+                    ]
+                },
+                "sunrise": {
+                    "type": "string"
+                },
+                "sunset": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.BatchLocation": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string",
+                    "example": "Kyiv"
+                },
+                "lat": {
+                    "type": "number"
+                },
+                "lon": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.BatchWeatherRequest": {
+            "type": "object",
+            "properties": {
+                "locations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BatchLocation"
+                    }
+                }
+            }
+        },
+        "model.BatchWeatherResponse": {
+            "type": "object",
+            "properties": {
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BatchWeatherResult"
+                    }
+                }
+            }
+        },
+        "model.BatchWeatherResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "location": {
+                    "type": "string",
+                    "example": "Kyiv"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "weather": {
+                    "$ref": "#/definitions/model.Weather"
+                }
+            }
+        },
+        "model.Forecast": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ForecastDay"
+                    }
+                },
+                "units": {
+                    "$ref": "#/definitions/model.Units"
+                }
+            }
+        },
+        "model.ForecastDay": {
+            "type": "object",
+            "properties": {
+                "chance_of_rain": {
+                    "type": "number"
+                },
+                "condition": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2025-06-01"
+                },
+                "max_temperature": {
+                    "type": "number"
+                },
+                "max_wind_speed": {
+                    "type": "number"
+                },
+                "min_temperature": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.ForecastHour": {
+            "type": "object",
+            "properties": {
+                "chance_of_rain": {
+                    "type": "number"
+                },
+                "condition": {
+                    "type": "string"
+                },
+                "precipitation": {
+                    "type": "number"
+                },
+                "temperature": {
+                    "type": "number"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.History": {
+            "type": "object",
+            "properties": {
+                "avg_humidity": {
+                    "type": "number"
+                },
+                "avg_temperature": {
+                    "type": "number"
+                },
+                "city": {
+                    "type": "string"
+                },
+                "condition": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2025-06-01"
+                },
+                "hours": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.HistoryHour"
+                    }
+                },
+                "max_temperature": {
+                    "type": "number"
+                },
+                "min_temperature": {
+                    "type": "number"
+                },
+                "source": {
+                    "type": "string",
+                    "enum": [
+                        "provider",
+                        "local"
+                    ]
+                },
+                "units": {
+                    "$ref": "#/definitions/model.Units"
+                }
+            }
+        },
+        "model.HistoryHour": {
+            "type": "object",
+            "properties": {
+                "condition": {
+                    "type": "string"
+                },
+                "humidity": {
+                    "type": "number"
+                },
+                "temperature": {
+                    "type": "number"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.HourlyForecast": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "hours": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ForecastHour"
+                    }
+                },
+                "units": {
+                    "$ref": "#/definitions/model.Units"
+                }
+            }
+        },
+        "model.Location": {
+            "type": "object",
+            "properties": {
+                "country": {
+                    "type": "string",
+                    "example": "Ukraine"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "weatherapi:2801268"
+                },
+                "lat": {
+                    "type": "number",
+                    "example": 50.43
+                },
+                "lon": {
+                    "type": "number",
+                    "example": 30.52
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Kyiv"
+                },
+                "region": {
+                    "type": "string",
+                    "example": "Kyyiv"
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "Europe/Kyiv"
+                }
+            }
+        },
+        "model.ProviderUsage": {
+            "type": "object",
+            "properties": {
+                "daily_budget": {
+                    "type": "integer",
+                    "example": 30000
+                },
+                "daily_calls": {
+                    "type": "integer",
+                    "example": 1234
+                },
+                "day": {
+                    "type": "string",
+                    "example": "2025-05-17"
+                },
+                "degraded": {
+                    "description": "Degraded is true once the degrade share of a budget is used up:\ncached data is then served rather than refreshed.",
+                    "type": "boolean"
+                },
+                "exhausted": {
+                    "type": "boolean"
+                },
+                "month": {
+                    "type": "string",
+                    "example": "2025-05"
+                },
+                "monthly_budget": {
+                    "type": "integer",
+                    "example": 1000000
+                },
+                "monthly_calls": {
+                    "type": "integer",
+                    "example": 31337
+                },
+                "provider": {
+                    "type": "string",
+                    "example": "weatherapi"
+                },
+                "throttled": {
+                    "description": "Throttled is true once the background share of a budget is used up:\nscheduled sends then no longer call the provider, interactive requests still do.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.Subscription": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "confirmed": {
+                    "type": "boolean"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "frequency": {
+                    "type": "string",
+                    "enum": [
+                        "hourly",
+                        "daily",
+                        "alerts"
+                    ]
+                },
+                "include_air_quality": {
+                    "type": "boolean"
+                },
+                "language": {
+                    "type": "string",
+                    "enum": [
+                        "en",
+                        "uk"
+                    ]
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "units": {
+                    "type": "string",
+                    "default": "metric",
+                    "enum": [
+                        "metric",
+                        "imperial",
+                        "scientific"
+                    ]
+                }
+            }
+        },
+        "model.Units": {
+            "type": "object",
+            "properties": {
+                "precipitation": {
+                    "type": "string",
+                    "example": "mm"
+                },
+                "pressure": {
+                    "type": "string",
+                    "example": "hPa"
+                },
+                "system": {
+                    "type": "string",
+                    "enum": [
+                        "metric",
+                        "imperial",
+                        "scientific"
+                    ]
+                },
+                "temperature": {
+                    "type": "string",
+                    "example": "°C"
+                },
+                "visibility": {
+                    "type": "string",
+                    "example": "km"
+                },
+                "wind_speed": {
+                    "type": "string",
+                    "example": "km/h"
+                }
+            }
+        },
+        "model.Weather": {
+            "type": "object",
+            "properties": {
+                "air_quality": {
+                    "$ref": "#/definitions/model.AirQuality"
+                },
+                "cloud_cover": {
+                    "type": "integer",
+                    "example": 75
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Patchy rain nearby"
+                },
+                "feels_like": {
+                    "type": "number",
+                    "example": 14.2
+                },
+                "humidity": {
+                    "type": "number",
+                    "example": 52
+                },
+                "observed_at": {
+                    "type": "string"
+                },
+                "precipitation": {
+                    "type": "number",
+                    "example": 0.2
+                },
+                "pressure": {
+                    "type": "number",
+                    "example": 1012
+                },
+                "sources": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "temperature": {
+                    "type": "number",
+                    "example": 15.8
+                },
+                "units": {
+                    "$ref": "#/definitions/model.Units"
+                },
+                "uv_index": {
+                    "type": "number",
+                    "example": 3
+                },
+                "visibility": {
+                    "type": "number",
+                    "example": 10
+                },
+                "wind_direction": {
+                    "type": "integer",
+                    "example": 270
+                },
+                "wind_gust": {
+                    "type": "number",
+                    "example": 22.3
+                },
+                "wind_speed": {
+                    "type": "number",
+                    "example": 14.4
+                }
+            }
+        },
+        "resilience.State": {
+            "type": "string",
+            "enum": [
+                "closed",
+                "open",
+                "half-open"
+            ],
+            "x-enum-varnames": [
+                "StateClosed",
+                "StateOpen",
+                "StateHalfOpen"
+            ]
+        },
+        "resilience.Status": {
+            "type": "object",
+            "properties": {
+                "consecutive_failures": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "name": {
+                    "type": "string",
+                    "example": "weatherapi"
+                },
+                "opened_at": {
+                    "type": "string"
+                },
+                "retry_after_seconds": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "state": {
+                    "enum": [
+                        "closed",
+                        "open",
+                        "half-open"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/resilience.State"
+                        }
+                    ]
+                }
+            }
+        },
+        "response.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                }
+            }
+        }
+    },
+    "tags": [
+        {
+            "description": "Weather forecast operations",
+            "name": "weather"
+        },
+        {
+            "description": "Location search operations",
+            "name": "locations"
+        },
+        {
+            "description": "Subscription management operations",
+            "name": "subscription"
+        },
+        {
+            "description": "Monitoring of upstream weather providers",
+            "name": "status"
         }
     ]
 }`
@@ -7,8 +7,14 @@
 // @tag.name weather
 // @tag.description Weather forecast operations
 
+// @tag.name locations
+// @tag.description Location search operations
+
 // @tag.name subscription
 // @tag.description Subscription management operations
+
+// @tag.name status
+// @tag.description Monitoring of upstream weather providers
 package main
 
 import (
@@ -17,8 +23,12 @@ import (
 	"Weather-API-Application/internal/config"
 	"Weather-API-Application/internal/handler"
 	"Weather-API-Application/internal/infrastructure/database"
+	"Weather-API-Application/internal/infrastructure/provider"
 	"Weather-API-Application/internal/infrastructure/repository"
 	"Weather-API-Application/internal/logger"
+	"Weather-API-Application/internal/notification"
+	"Weather-API-Application/internal/quota"
+	"Weather-API-Application/internal/resilience"
 	"Weather-API-Application/internal/server"
 	"Weather-API-Application/internal/services/scheduler_service"
 	"Weather-API-Application/internal/services/subscription_service"
@@ -44,30 +54,59 @@ func main() {
 	}
 
 	// Initialize email client
-	emailClient := client.NewEmailClient(cfg)
+	emailClient := client.NewClient(cfg)
 
 	// Initialize repositories
 	subscriptionRepository := repository.NewSubscriptionRepository(db)
+	observationRepository := repository.NewObservationRepository(db)
+	usageRepository := repository.NewUsageRepository(db)
+
+	// Initialize weather provider
+	breakers := resilience.NewRegistry()
+	meter := quota.NewMeter(usageRepository, quota.BudgetsFromConfig(cfg), cfg.WeatherBudgetReserve, cfg.WeatherBudgetDegradeAt)
+	meterCtx, stopMeter := context.WithCancel(ctx)
+	meterDone := make(chan struct{})
+	go func() {
+		defer close(meterDone)
+		meter.Run(meterCtx, cfg.WeatherUsageFlushInterval)
+	}()
+	weatherProvider, err := provider.NewWeatherProvider(cfg, breakers, meter)
+	if err != nil {
+		logger.Fatal(ctx, fmt.Errorf("failed to initialize weather provider: %w", err))
+	}
 
 	// Initialize services
-	schedulerService := scheduler_service.NewSchedulerService(subscriptionRepository, *emailClient, cfg)
-	subscriptionService := subscription_service.NewSubscriptionService(subscriptionRepository, *emailClient, cfg).WithScheduler(schedulerService)
-	weatherService := weather_service.NewService(cfg)
+	weatherService := weather_service.NewService(cfg, weatherProvider).WithObservations(observationRepository)
+	schedulerService := scheduler_service.NewSchedulerService(subscriptionRepository, emailClient, weatherService, notification.NewComposer(), cfg).
+		WithLeader(database.NewLeader(db, database.SchedulerLockKey, cfg.SchedulerReconcileInterval))
+	subscriptionService := subscription_service.NewSubscriptionService(subscriptionRepository, emailClient, cfg).
+		WithScheduler(schedulerService).
+		WithLocationResolver(weatherProvider)
 
 	// Initialize server
 	srvr := server.NewServer(cfg)
 
 	// Initialize handlers and register routes
-	weatherHandler := handler.NewWeatherHandler(weatherService)
+	weatherHandler := handler.NewWeatherHandler(cfg, weatherService)
 	subscriptionHandler := handler.NewSubscriptionHandler(cfg, subscriptionService)
+	statusHandler := handler.NewStatusHandler(cfg, breakers, meter)
 	weatherHandler.RegisterRoutes(srvr.Router)
 	subscriptionHandler.RegisterRoutes(srvr.Router)
+	statusHandler.RegisterRoutes(srvr.Router)
 
-	// Start scheduler for confirmed subscriptions
+	// Start scheduler for confirmed subscriptions, shared with other instances through the database
 	if err := schedulerService.StartScheduler(ctx); err != nil {
 		logger.Fatal(ctx, fmt.Errorf("failed to start subscription scheduler: %w", err))
 	}
 
+	// Resolve canonical locations of subscriptions stored without one in the background, on one instance
+	backfillLeader := database.NewLeader(db, database.LocationBackfillLockKey, cfg.LocationBackfillInterval)
+	go backfillLeader.Run(quota.WithPriority(ctx, quota.Background), subscriptionService.RunLocationBackfill)
+
 	// Run API server
 	srvr.Run(ctx)
+
+	// Store the calls counted since the last flush
+	stopMeter()
+	<-meterDone
 }
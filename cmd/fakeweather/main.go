@@ -0,0 +1,56 @@
+// Command fakeweather serves a fake WeatherAPI.com API for integration testing.
+// Point the application at it with WEATHER_API_BASE_URL=http://localhost:8081/v1.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"Weather-API-Application/internal/fakeweather"
+	"Weather-API-Application/internal/logger"
+)
+
+func main() {
+	ctx := context.Background()
+
+	addr := flag.String("addr", ":8081", "address to listen on")
+	citiesPath := flag.String("cities", "", "JSON file with the served cities (default: Kyiv, London and New York)")
+	apiKey := flag.String("key", "", "API key required from clients (default: any key is accepted)")
+	latency := flag.Duration("latency", 0, "delay added to every response")
+	status := flag.Int("status", 0, "HTTP status of an error returned for every request")
+	code := flag.Int("code", 0, "WeatherAPI.com error code sent with -status")
+	malformed := flag.Bool("malformed", false, "respond with malformed JSON")
+	flag.Parse()
+
+	cfg := fakeweather.Config{
+		APIKey: *apiKey,
+		Fault: fakeweather.Fault{
+			Latency:   fakeweather.Duration(*latency),
+			Status:    *status,
+			Code:      *code,
+			Malformed: *malformed,
+		},
+	}
+	if *citiesPath != "" {
+		cities, err := fakeweather.LoadCities(*citiesPath)
+		if err != nil {
+			logger.Fatal(ctx, err)
+		}
+		cfg.Cities = cities
+	}
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           fakeweather.NewServer(cfg),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	logger.Info(ctx, "Fake WeatherAPI listening", slog.String("addr", *addr))
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Fatal(ctx, fmt.Errorf("fake WeatherAPI server failed: %w", err))
+	}
+}
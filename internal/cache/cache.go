@@ -0,0 +1,184 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+type Status string
+
+const (
+	StatusHit   Status = "HIT"
+	StatusMiss  Status = "MISS"
+	StatusStale Status = "STALE"
+)
+
+// Info describes how a value was served by the cache.
+type Info struct {
+	Status Status
+	Age    time.Duration
+}
+
+// LoadFunc loads a value from the origin on a cache miss.
+type LoadFunc[V any] func(ctx context.Context) (V, error)
+
+// Cache is an in-memory LRU cache with a per-entry TTL.
+//
+// Entries younger than ttl are served as fresh. Entries older than ttl but younger than
+// ttl+staleTTL are served as stale while a single background refresh revalidates them;
+// if the refresh fails the stale value keeps being served until staleTTL runs out.
+// Concurrent loads of the same key are coalesced into one origin call.
+type Cache[V any] struct {
+	ttl           time.Duration
+	staleTTL      time.Duration
+	maxEntries    int
+	serveExpired  func(error) bool
+	maxExpiredAge time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	group   singleflight.Group
+	now     func() time.Time
+}
+
+type entry[V any] struct {
+	key      string
+	value    V
+	storedAt time.Time
+}
+
+func New[V any](ttl, staleTTL time.Duration, maxEntries int) *Cache[V] {
+	return &Cache[V]{
+		ttl:        ttl,
+		staleTTL:   staleTTL,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+		now:        time.Now,
+	}
+}
+
+// ServeExpiredOn keeps expired entries younger than maxAge and serves them as stale when
+// reloading fails with an error for which fn returns true. Older entries are removed.
+func (c *Cache[V]) ServeExpiredOn(maxAge time.Duration, fn func(error) bool) *Cache[V] {
+	c.serveExpired = fn
+	c.maxExpiredAge = maxAge
+	return c
+}
+
+// Get returns the value cached under key, calling load when the value is missing or expired.
+// The caller stops waiting when ctx is done, while the shared load itself is not cancelled
+// so that other callers waiting for the same key still get the result.
+// Loads of a key with an older value to serve instead are made with a ctx for which HasFallback is true.
+func (c *Cache[V]) Get(ctx context.Context, key string, load LoadFunc[V]) (V, Info, error) {
+	cached, age, found := c.lookup(key)
+	if found {
+		ctx = context.WithValue(ctx, fallbackKey{}, true)
+		if age < c.ttl {
+			return cached, Info{Status: StatusHit, Age: age}, nil
+		}
+		if age < c.ttl+c.staleTTL {
+			c.refresh(ctx, key, load)
+			return cached, Info{Status: StatusStale, Age: age}, nil
+		}
+	}
+
+	ch := c.group.DoChan(key, func() (any, error) {
+		return c.loadAndStore(context.WithoutCancel(ctx), key, load)
+	})
+
+	var zero V
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			if found && c.serveExpired != nil && c.serveExpired(res.Err) {
+				return cached, Info{Status: StatusStale, Age: age}, nil
+			}
+			return zero, Info{}, res.Err
+		}
+		return res.Val.(V), Info{Status: StatusMiss}, nil
+	case <-ctx.Done():
+		return zero, Info{}, ctx.Err()
+	}
+}
+
+type fallbackKey struct{}
+
+// HasFallback reports whether a load was called with ctx while the cache holds an older value
+// to serve if the load fails.
+func HasFallback(ctx context.Context) bool {
+	fallback, _ := ctx.Value(fallbackKey{}).(bool)
+	return fallback
+}
+
+// Len returns the number of cached entries.
+func (c *Cache[V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// refresh revalidates a stale entry in the background. Concurrent refreshes are coalesced.
+func (c *Cache[V]) refresh(ctx context.Context, key string, load LoadFunc[V]) {
+	c.group.DoChan(key, func() (any, error) {
+		return c.loadAndStore(context.WithoutCancel(ctx), key, load)
+	})
+}
+
+func (c *Cache[V]) loadAndStore(ctx context.Context, key string, load LoadFunc[V]) (any, error) {
+	value, err := load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.store(key, value)
+	return value, nil
+}
+
+// lookup returns the cached value and its age, marking the entry as recently used.
+// Expired entries are removed, unless they are young enough to be served by ServeExpiredOn.
+func (c *Cache[V]) lookup(key string) (V, time.Duration, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		var zero V
+		return zero, 0, false
+	}
+	e := el.Value.(*entry[V])
+	age := c.now().Sub(e.storedAt)
+	if age >= c.ttl+c.staleTTL && (c.serveExpired == nil || age >= c.maxExpiredAge) {
+		c.lru.Remove(el)
+		delete(c.entries, key)
+		var zero V
+		return zero, 0, false
+	}
+	c.lru.MoveToFront(el)
+	return e.value, age, true
+}
+
+// store saves the value and evicts the least recently used entries above maxEntries.
+func (c *Cache[V]) store(key string, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		e := el.Value.(*entry[V])
+		e.value = value
+		e.storedAt = c.now()
+		c.lru.MoveToFront(el)
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(&entry[V]{key: key, value: value, storedAt: c.now()})
+	for c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*entry[V]).key)
+	}
+}
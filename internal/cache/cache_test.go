@@ -0,0 +1,151 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheGet(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := New[int](time.Minute, time.Minute, 10)
+	c.now = func() time.Time { return now }
+
+	var calls atomic.Int32
+	load := func(context.Context) (int, error) {
+		return int(calls.Add(1)), nil
+	}
+
+	tests := []struct {
+		name       string
+		advance    time.Duration
+		wantValue  int
+		wantStatus Status
+		wantCalls  int32
+	}{
+		{"miss", 0, 1, StatusMiss, 1},
+		{"hit", 30 * time.Second, 1, StatusHit, 1},
+		{"stale triggers revalidation", time.Minute, 1, StatusStale, 2},
+		{"revalidated", 0, 2, StatusHit, 2},
+		{"expired", 2 * time.Minute, 3, StatusMiss, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now = now.Add(tt.advance)
+			got, info, err := c.Get(context.Background(), "kyiv", load)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantValue, got)
+			require.Equal(t, tt.wantStatus, info.Status)
+			// Wait for the background revalidation triggered by a stale read
+			require.Eventually(t, func() bool {
+				v, _, ok := c.lookup("kyiv")
+				return ok && int32(v) == tt.wantCalls
+			}, time.Second, time.Millisecond)
+		})
+	}
+}
+
+func TestCacheCoalescesConcurrentLoads(t *testing.T) {
+	c := New[int](time.Minute, 0, 10)
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	load := func(context.Context) (int, error) {
+		calls.Add(1)
+		<-release
+		return 42, nil
+	}
+
+	results := make([]int, 10)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _, _ = c.Get(context.Background(), "kyiv", load)
+		}(i)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	require.Equal(t, int32(1), calls.Load())
+	for _, got := range results {
+		require.Equal(t, 42, got)
+	}
+}
+
+func TestCacheDoesNotStoreErrors(t *testing.T) {
+	c := New[int](time.Minute, 0, 10)
+
+	_, _, err := c.Get(context.Background(), "kyiv", func(context.Context) (int, error) {
+		return 0, errors.New("upstream down")
+	})
+	require.Error(t, err)
+	require.Equal(t, 0, c.Len())
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := New[string](time.Minute, 0, 2)
+	load := func(v string) LoadFunc[string] {
+		return func(context.Context) (string, error) { return v, nil }
+	}
+	ctx := context.Background()
+
+	_, _, _ = c.Get(ctx, "a", load("a"))
+	_, _, _ = c.Get(ctx, "b", load("b"))
+	_, _, _ = c.Get(ctx, "a", load("a"))
+	_, _, _ = c.Get(ctx, "c", load("c"))
+
+	require.Equal(t, 2, c.Len())
+	_, _, ok := c.lookup("b")
+	require.False(t, ok)
+	_, _, ok = c.lookup("a")
+	require.True(t, ok)
+}
+
+func TestCacheServesExpiredOnError(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	errQuota := errors.New("quota exceeded")
+	c := New[int](time.Minute, time.Minute, 10).ServeExpiredOn(2*time.Hour, func(err error) bool {
+		return errors.Is(err, errQuota)
+	})
+	c.now = func() time.Time { return now }
+
+	// Loads tell whether an older value is served if they fail
+	var fallback bool
+	_, _, err := c.Get(context.Background(), "kyiv", func(ctx context.Context) (int, error) {
+		fallback = HasFallback(ctx)
+		return 1, nil
+	})
+	require.NoError(t, err)
+	require.False(t, fallback)
+
+	now = now.Add(time.Hour)
+	got, info, err := c.Get(context.Background(), "kyiv", func(ctx context.Context) (int, error) {
+		fallback = HasFallback(ctx)
+		return 0, errQuota
+	})
+	require.True(t, fallback)
+	require.NoError(t, err)
+	require.Equal(t, 1, got)
+	require.Equal(t, Info{Status: StatusStale, Age: time.Hour}, info)
+
+	_, _, err = c.Get(context.Background(), "kyiv", func(context.Context) (int, error) { return 0, errors.New("boom") })
+	require.Error(t, err)
+
+	// Past the maximum age the error is returned even when expired entries may be served
+	now = now.Add(time.Hour)
+	_, _, err = c.Get(context.Background(), "kyiv", func(ctx context.Context) (int, error) {
+		fallback = HasFallback(ctx)
+		return 0, errQuota
+	})
+	require.ErrorIs(t, err, errQuota)
+	require.False(t, fallback)
+	require.Zero(t, c.Len())
+}
@@ -0,0 +1,34 @@
+package client
+
+import (
+	"context"
+	"log/slog"
+
+	"Weather-API-Application/internal/config"
+	"Weather-API-Application/internal/logger"
+)
+
+// LogEmailClient writes emails to the log instead of sending them, for local development.
+type LogEmailClient struct{}
+
+func NewLogEmailClient() *LogEmailClient {
+	return &LogEmailClient{}
+}
+
+// SendEmail logs the email.
+func (c *LogEmailClient) SendEmail(ctx context.Context, to, subject, body string) error {
+	logger.Info(ctx, "Email logged",
+		slog.String("to", to),
+		slog.String("message_id", MessageIDFrom(ctx)),
+		slog.String("subject", subject),
+		slog.String("body", body))
+	return nil
+}
+
+// NewClient creates the email client selected by cfg.EmailTransport.
+func NewClient(cfg *config.Config) Client {
+	if cfg.EmailTransport == "log" {
+		return NewLogEmailClient()
+	}
+	return NewEmailClient(cfg)
+}
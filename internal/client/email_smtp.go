@@ -2,26 +2,93 @@ package client
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
+	"crypto/tls"
+	"errors"
 	"log/slog"
-	"net/http"
+	"net"
 	"net/smtp"
+	"strings"
+	"time"
 
 	"Weather-API-Application/internal/config"
 	"Weather-API-Application/internal/logger"
-	"Weather-API-Application/internal/model"
 )
 
-// SmtpSender abstracts smtp.SendMail for testability.
+// SmtpSender abstracts smtp.SendMail for testability. Sending must stop when ctx is done.
 type SmtpSender interface {
-	SendMail(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+	SendMail(ctx context.Context, addr string, a smtp.Auth, from string, to []string, msg []byte) error
 }
 
-type smtpSender struct{}
+// smtpSender works like smtp.SendMail, but the whole exchange with the server, from dialing to
+// the end of the message, is bounded by timeout and ctx.
+type smtpSender struct {
+	timeout time.Duration
+}
+
+func (s smtpSender) SendMail(ctx context.Context, addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
 
-func (smtpSender) SendMail(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
-	return smtp.SendMail(addr, a, from, to, msg)
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err
+	}
+	// Unblock reads and writes as soon as ctx is cancelled, not only at the deadline
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if a != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("smtp: server doesn't support AUTH")
+		}
+		if err := c.Auth(a); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	for _, addr := range to {
+		if err := c.Rcpt(addr); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	// The server has accepted the message, a failed QUIT does not change that
+	_ = c.Quit()
+	return nil
 }
 
 type EmailClient struct {
@@ -33,7 +100,7 @@ type EmailClient struct {
 }
 
 func NewEmailClient(cfg *config.Config) *EmailClient {
-	return &EmailClient{From: cfg.EmailClientFrom, Password: cfg.EmailClientPassword, Host: cfg.EmailClientHost, Port: cfg.EmailClientPort, sender: smtpSender{}}
+	return &EmailClient{From: cfg.EmailClientFrom, Password: cfg.EmailClientPassword, Host: cfg.EmailClientHost, Port: cfg.EmailClientPort, sender: smtpSender{timeout: cfg.EmailClientTimeout}}
 }
 
 // NewEmailClientWithSender allows injecting a custom SmtpSender (useful for tests).
@@ -46,16 +113,21 @@ type Client interface {
 	SendEmail(ctx context.Context, to, subject, body string) error
 }
 
-// SendEmail sends an email using SMTP.
+// SendEmail sends an email using SMTP, giving up after EmailClientTimeout or when ctx is done.
+// The Message-ID is derived from the key set with WithMessageID, if any.
 func (c *EmailClient) SendEmail(ctx context.Context, to, subject, body string) error {
-	msg := []byte("To: " + to + "\r\n" +
+	headers := "To: " + to + "\r\n"
+	if key := MessageIDFrom(ctx); key != "" {
+		headers += "Message-ID: <" + key + "@" + c.messageIDDomain() + ">\r\n"
+	}
+	msg := []byte(headers +
 		"Subject: " + subject + "\r\n" +
 		"MIME-version: 1.0;\nContent-Type: text/html; charset=\"UTF-8\";\n\n" +
 		"\r\n" + body)
 
 	auth := smtp.PlainAuth("", c.From, c.Password, c.Host)
 
-	if err := c.sender.SendMail(c.Host+":"+c.Port, auth, c.From, []string{to}, msg); err != nil {
+	if err := c.sender.SendMail(ctx, c.Host+":"+c.Port, auth, c.From, []string{to}, msg); err != nil {
 		return err
 	}
 
@@ -65,35 +137,10 @@ func (c *EmailClient) SendEmail(ctx context.Context, to, subject, body string) e
 	return nil
 }
 
-// SendUpdate fetches current weather for the subscription city and emails the user.
-func SendUpdate(ctx context.Context, apiKey string, sub *model.Subscription, emailClient Client) error {
-	if apiKey == "" {
-		return fmt.Errorf("weather API key is missing in config")
-	}
-
-	url := fmt.Sprintf("https://api.weatherapi.com/v1/current.json?key=%s&q=%s&aqi=no", apiKey, sub.City)
-
-	resp, err := http.Get(url)
-	if err != nil {
-		return fmt.Errorf("invalid request: failed to fetch weather data: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("city not found: failed to fetch weather data: %s", resp.Status)
-	}
-
-	var weatherApiResp model.WeatherAPIResponse
-	if err = json.NewDecoder(resp.Body).Decode(&weatherApiResp); err != nil {
-		return fmt.Errorf("failed to decode weather data: %w", err)
+// messageIDDomain returns the domain of the sender address, or the SMTP host if it has none.
+func (c *EmailClient) messageIDDomain() string {
+	if i := strings.LastIndex(c.From, "@"); i >= 0 {
+		return c.From[i+1:]
 	}
-
-	weatherMailText := fmt.Sprintf(`Weather for %s:<br>- temperature: %.1f°C<br>- humidity: %.0f%%<br>- description: %s`,
-		sub.City, weatherApiResp.Current.TempC, weatherApiResp.Current.Humidity, weatherApiResp.Current.Condition.Text)
-	subject := fmt.Sprintf("%s forecast", sub.City)
-
-	if err := emailClient.SendEmail(ctx, sub.Email, subject, weatherMailText); err != nil {
-		return fmt.Errorf("failed to send email to %s for city %s: %w", sub.Email, sub.City, err)
-	}
-	return nil
+	return c.Host
 }
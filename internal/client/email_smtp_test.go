@@ -0,0 +1,126 @@
+package client
+
+import (
+	"context"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"Weather-API-Application/internal/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestSmtpSenderTimeout checks that a mail server that accepts the connection but never
+// answers does not block the sender past its timeout or past ctx.
+func TestSmtpSenderTimeout(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	// Connections are held open without a greeting until the listener is closed
+	go func() {
+		var conns []net.Conn
+		defer func() {
+			for _, conn := range conns {
+				conn.Close()
+			}
+		}()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conns = append(conns, conn)
+		}
+	}()
+
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		ctxWait time.Duration
+	}{
+		{name: "timeout", timeout: 50 * time.Millisecond, ctxWait: time.Minute},
+		{name: "context deadline", timeout: time.Minute, ctxWait: 50 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), tt.ctxWait)
+			defer cancel()
+
+			start := time.Now()
+			err := smtpSender{timeout: tt.timeout}.SendMail(ctx, listener.Addr().String(), nil, "from@example.com", []string{"to@example.com"}, []byte("Subject: test\r\n\r\nbody"))
+			require.Error(t, err)
+			require.Less(t, time.Since(start), 5*time.Second)
+		})
+	}
+}
+
+// TestSmtpSenderRequiresAuth checks that credentials are never dropped silently when the
+// server does not offer AUTH: the message must not be sent unauthenticated.
+func TestSmtpSenderRequiresAuth(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	commands := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		tp := textproto.NewConn(conn)
+		var received []string
+		defer func() { commands <- received }()
+
+		_ = tp.PrintfLine("220 localhost ESMTP")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			received = append(received, line)
+			switch {
+			case strings.HasPrefix(line, "EHLO"):
+				_ = tp.PrintfLine("250-localhost\r\n250 8BITMIME")
+			case strings.HasPrefix(line, "QUIT"):
+				_ = tp.PrintfLine("221 bye")
+				return
+			default:
+				_ = tp.PrintfLine("250 OK")
+			}
+		}
+	}()
+
+	auth := smtp.PlainAuth("", "from@example.com", "secret", "127.0.0.1")
+	err = smtpSender{timeout: time.Minute}.SendMail(context.Background(), listener.Addr().String(), auth, "from@example.com", []string{"to@example.com"}, []byte("Subject: test\r\n\r\nbody"))
+	require.ErrorContains(t, err, "AUTH")
+	for _, cmd := range <-commands {
+		require.False(t, strings.HasPrefix(cmd, "MAIL"), "message sent without authentication")
+	}
+}
+
+type fakeSmtpSender struct {
+	msg []byte
+}
+
+func (f *fakeSmtpSender) SendMail(_ context.Context, _ string, _ smtp.Auth, _ string, _ []string, msg []byte) error {
+	f.msg = msg
+	return nil
+}
+
+func TestSendEmailMessageID(t *testing.T) {
+	sender := &fakeSmtpSender{}
+	c := NewEmailClientWithSender(&config.Config{EmailClientFrom: "no-reply@example.com", EmailClientHost: "smtp.example.com"}, sender)
+
+	require.NoError(t, c.SendEmail(context.Background(), "user@example.com", "Kyiv forecast", "body"))
+	require.NotContains(t, string(sender.msg), "Message-ID")
+
+	ctx := WithMessageID(context.Background(), "update-1-1760000000")
+	require.NoError(t, c.SendEmail(ctx, "user@example.com", "Kyiv forecast", "body"))
+	require.Contains(t, string(sender.msg), "Message-ID: <update-1-1760000000@example.com>\r\n")
+}
@@ -0,0 +1,18 @@
+package client
+
+import "context"
+
+type messageIDKey struct{}
+
+// WithMessageID returns a context whose email is sent with a Message-ID built from key. Sending
+// the same message again with the same key, e.g. when retrying an interrupted delivery, lets mail
+// servers and clients recognize the copy as a duplicate.
+func WithMessageID(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, messageIDKey{}, key)
+}
+
+// MessageIDFrom returns the Message-ID key of ctx, empty if none was set.
+func MessageIDFrom(ctx context.Context) string {
+	key, _ := ctx.Value(messageIDKey{}).(string)
+	return key
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/caarlos0/env/v11"
 )
@@ -12,18 +13,65 @@ type Config struct {
 	BaseURL        string `env:"APP_BASE_URL"`
 	DailyStartHour int    `env:"DAILY_START_HOUR" envDefault:"8"`
 
+	AlertsPollInterval time.Duration `env:"ALERTS_POLL_INTERVAL" envDefault:"15m"`
+	SchedulerWorkers   int           `env:"SCHEDULER_WORKERS" envDefault:"16"`
+
+	SchedulerPollInterval  time.Duration `env:"SCHEDULER_POLL_INTERVAL" envDefault:"30s"`
+	SchedulerDeliveryLease time.Duration `env:"SCHEDULER_DELIVERY_LEASE" envDefault:"10m"`
+	// SchedulerReconcileInterval is also how often the leader election is checked.
+	SchedulerReconcileInterval time.Duration `env:"SCHEDULER_RECONCILE_INTERVAL" envDefault:"1m"`
+
+	// LocationBackfillInterval is how often unresolved subscription locations are retried,
+	// and also how often the backfill leader election is checked.
+	LocationBackfillInterval  time.Duration `env:"LOCATION_BACKFILL_INTERVAL" envDefault:"10m"`
+	LocationBackfillBatchSize int           `env:"LOCATION_BACKFILL_BATCH_SIZE" envDefault:"100"`
+
 	PostgresContainerHost string `env:"POSTGRES_CONTAINER_HOST"`
 	PostgresContainerPort int    `env:"POSTGRES_CONTAINER_PORT"`
 	PostgresUser          string `env:"POSTGRES_USER"`
 	PostgresPassword      string `env:"POSTGRES_PASSWORD"`
 	PostgresDB            string `env:"POSTGRES_DB"`
 
-	WeatherApiKey string `env:"WEATHER_API_KEY"`
+	WeatherProvider          string        `env:"WEATHER_PROVIDER" envDefault:"weatherapi"`
+	WeatherFallbackProviders []string      `env:"WEATHER_FALLBACK_PROVIDERS" envSeparator:","`
+	WeatherProviderMode      string        `env:"WEATHER_PROVIDER_MODE" envDefault:"failover"`
+	WeatherProviderTimeout   time.Duration `env:"WEATHER_PROVIDER_TIMEOUT" envDefault:"5s"`
+	WeatherHTTPTimeout       time.Duration `env:"WEATHER_HTTP_TIMEOUT" envDefault:"10s"`
+	WeatherApiKey            string        `env:"WEATHER_API_KEY"`
+	WeatherAPIBaseURL        string        `env:"WEATHER_API_BASE_URL" envDefault:"https://api.weatherapi.com/v1"`
+	OpenWeatherMapApiKey     string        `env:"OPENWEATHERMAP_API_KEY"`
+	WeatherFixturesDir       string        `env:"WEATHER_FIXTURES_DIR" envDefault:"fixtures/weatherapi"`
+
+	WeatherRetryMaxAttempts        int           `env:"WEATHER_RETRY_MAX_ATTEMPTS" envDefault:"3"`
+	WeatherRetryBaseDelay          time.Duration `env:"WEATHER_RETRY_BASE_DELAY" envDefault:"200ms"`
+	WeatherRetryMaxDelay           time.Duration `env:"WEATHER_RETRY_MAX_DELAY" envDefault:"2s"`
+	WeatherBreakerFailureThreshold int           `env:"WEATHER_BREAKER_FAILURE_THRESHOLD" envDefault:"5"`
+	WeatherBreakerOpenTimeout      time.Duration `env:"WEATHER_BREAKER_OPEN_TIMEOUT" envDefault:"30s"`
 
+	WeatherDailyBudgets   map[string]int64 `env:"WEATHER_DAILY_BUDGETS" envSeparator:"," envKeyValSeparator:":"`
+	WeatherMonthlyBudgets map[string]int64 `env:"WEATHER_MONTHLY_BUDGETS" envSeparator:"," envKeyValSeparator:":"`
+	WeatherBudgetReserve  float64          `env:"WEATHER_BUDGET_INTERACTIVE_RESERVE" envDefault:"0.2"`
+	// WeatherBudgetDegradeAt is the share of a budget after which cached data is served rather than refreshed.
+	WeatherBudgetDegradeAt    float64       `env:"WEATHER_BUDGET_DEGRADE_AT" envDefault:"0.75"`
+	WeatherUsageFlushInterval time.Duration `env:"WEATHER_USAGE_FLUSH_INTERVAL" envDefault:"5s"`
+
+	WeatherCacheTTL        time.Duration `env:"WEATHER_CACHE_TTL" envDefault:"10m"`
+	WeatherCacheStaleTTL   time.Duration `env:"WEATHER_CACHE_STALE_TTL" envDefault:"30m"`
+	WeatherCacheMaxEntries int           `env:"WEATHER_CACHE_MAX_ENTRIES" envDefault:"1000"`
+	// WeatherCacheMaxStaleAge caps the age of cached data served while providers are unavailable.
+	WeatherCacheMaxStaleAge time.Duration `env:"WEATHER_CACHE_MAX_STALE_AGE" envDefault:"6h"`
+
+	WeatherBatchMaxItems    int `env:"WEATHER_BATCH_MAX_ITEMS" envDefault:"50"`
+	WeatherBatchConcurrency int `env:"WEATHER_BATCH_CONCURRENCY" envDefault:"8"`
+
+	EmailTransport      string `env:"EMAIL_TRANSPORT" envDefault:"smtp"`
 	EmailClientFrom     string `env:"SMTP_FROM"`
 	EmailClientPassword string `env:"SMTP_PASSWORD"`
 	EmailClientHost     string `env:"SMTP_HOST"`
 	EmailClientPort     string `env:"SMTP_PORT"`
+	// EmailClientTimeout bounds each SMTP connection, from dialing to the end of the message,
+	// and must be shorter than SchedulerDeliveryLease.
+	EmailClientTimeout time.Duration `env:"SMTP_TIMEOUT" envDefault:"1m"`
 }
 
 // NewConfigFromEnv creates a new Config instance and populates it with values from environment variables.
@@ -44,23 +92,83 @@ func NewConfigFromEnv() (*Config, error) {
 
 // Validate checks that all required configuration values are present
 func (cfg *Config) Validate() error {
-	if cfg.WeatherApiKey == "" {
-		return fmt.Errorf("WEATHER_API_KEY is required")
+	if err := cfg.validateProvider(cfg.WeatherProvider); err != nil {
+		return fmt.Errorf("WEATHER_PROVIDER: %w", err)
+	}
+	for _, name := range cfg.WeatherFallbackProviders {
+		if err := cfg.validateProvider(name); err != nil {
+			return fmt.Errorf("WEATHER_FALLBACK_PROVIDERS: %w", err)
+		}
+	}
+	if cfg.WeatherProviderMode != "failover" && cfg.WeatherProviderMode != "consensus" {
+		return fmt.Errorf("WEATHER_PROVIDER_MODE must be 'failover' or 'consensus'")
 	}
 	if cfg.BaseURL == "" {
 		return fmt.Errorf("APP_BASE_URL is required")
 	}
-	if cfg.EmailClientFrom == "" {
-		return fmt.Errorf("SMTP_FROM is required")
+	if cfg.AlertsPollInterval <= 0 {
+		return fmt.Errorf("ALERTS_POLL_INTERVAL must be positive")
 	}
-	if cfg.EmailClientPassword == "" {
-		return fmt.Errorf("SMTP_PASSWORD is required")
+	if cfg.SchedulerWorkers <= 0 {
+		return fmt.Errorf("SCHEDULER_WORKERS must be positive")
 	}
-	if cfg.EmailClientHost == "" {
-		return fmt.Errorf("SMTP_HOST is required")
+	if cfg.SchedulerPollInterval <= 0 {
+		return fmt.Errorf("SCHEDULER_POLL_INTERVAL must be positive")
 	}
-	if cfg.EmailClientPort == "" {
-		return fmt.Errorf("SMTP_PORT is required")
+	if cfg.SchedulerDeliveryLease <= 0 {
+		return fmt.Errorf("SCHEDULER_DELIVERY_LEASE must be positive")
+	}
+	if cfg.SchedulerReconcileInterval <= 0 {
+		return fmt.Errorf("SCHEDULER_RECONCILE_INTERVAL must be positive")
+	}
+	if cfg.LocationBackfillInterval <= 0 {
+		return fmt.Errorf("LOCATION_BACKFILL_INTERVAL must be positive")
+	}
+	if cfg.LocationBackfillBatchSize <= 0 {
+		return fmt.Errorf("LOCATION_BACKFILL_BATCH_SIZE must be positive")
+	}
+	if cfg.WeatherHTTPTimeout <= 0 {
+		return fmt.Errorf("WEATHER_HTTP_TIMEOUT must be positive")
+	}
+	if cfg.WeatherRetryMaxAttempts < 1 {
+		return fmt.Errorf("WEATHER_RETRY_MAX_ATTEMPTS must be at least 1")
+	}
+	if cfg.WeatherRetryBaseDelay < 0 || cfg.WeatherRetryMaxDelay < cfg.WeatherRetryBaseDelay {
+		return fmt.Errorf("WEATHER_RETRY_BASE_DELAY must not be negative or exceed WEATHER_RETRY_MAX_DELAY")
+	}
+	if cfg.WeatherBreakerFailureThreshold > 0 && cfg.WeatherBreakerOpenTimeout <= 0 {
+		return fmt.Errorf("WEATHER_BREAKER_OPEN_TIMEOUT must be positive")
+	}
+	for name, budget := range cfg.WeatherDailyBudgets {
+		if err := cfg.validateBudget(name, budget); err != nil {
+			return fmt.Errorf("WEATHER_DAILY_BUDGETS: %w", err)
+		}
+	}
+	for name, budget := range cfg.WeatherMonthlyBudgets {
+		if err := cfg.validateBudget(name, budget); err != nil {
+			return fmt.Errorf("WEATHER_MONTHLY_BUDGETS: %w", err)
+		}
+	}
+	if cfg.WeatherBudgetReserve < 0 || cfg.WeatherBudgetReserve >= 1 {
+		return fmt.Errorf("WEATHER_BUDGET_INTERACTIVE_RESERVE must be at least 0 and less than 1")
+	}
+	if cfg.WeatherBudgetDegradeAt <= 0 || cfg.WeatherBudgetDegradeAt > 1 {
+		return fmt.Errorf("WEATHER_BUDGET_DEGRADE_AT must be more than 0 and at most 1")
+	}
+	if cfg.WeatherUsageFlushInterval <= 0 {
+		return fmt.Errorf("WEATHER_USAGE_FLUSH_INTERVAL must be positive")
+	}
+	if cfg.WeatherCacheMaxStaleAge < cfg.WeatherCacheTTL+cfg.WeatherCacheStaleTTL {
+		return fmt.Errorf("WEATHER_CACHE_MAX_STALE_AGE must be at least WEATHER_CACHE_TTL plus WEATHER_CACHE_STALE_TTL")
+	}
+	if cfg.WeatherBatchMaxItems <= 0 {
+		return fmt.Errorf("WEATHER_BATCH_MAX_ITEMS must be positive")
+	}
+	if cfg.WeatherBatchConcurrency <= 0 {
+		return fmt.Errorf("WEATHER_BATCH_CONCURRENCY must be positive")
+	}
+	if err := cfg.validateEmail(); err != nil {
+		return err
 	}
 	if cfg.PostgresContainerHost == "" {
 		return fmt.Errorf("POSTGRES_CONTAINER_HOST is required")
@@ -77,6 +185,81 @@ func (cfg *Config) Validate() error {
 	return nil
 }
 
+// validateProvider checks that the provider name is known and its API key is set
+func (cfg *Config) validateProvider(name string) error {
+	switch name {
+	case "weatherapi":
+		if cfg.WeatherApiKey == "" {
+			return fmt.Errorf("WEATHER_API_KEY is required")
+		}
+		if cfg.WeatherAPIBaseURL == "" {
+			return fmt.Errorf("WEATHER_API_BASE_URL is required")
+		}
+	case "openweathermap":
+		if cfg.OpenWeatherMapApiKey == "" {
+			return fmt.Errorf("OPENWEATHERMAP_API_KEY is required")
+		}
+	case "openmeteo":
+		// Open-Meteo does not require an API key
+	case "replay":
+		// Replayed fixtures do not require an API key or network access
+		if cfg.WeatherFixturesDir == "" {
+			return fmt.Errorf("WEATHER_FIXTURES_DIR is required")
+		}
+	case "record":
+		if cfg.WeatherApiKey == "" {
+			return fmt.Errorf("WEATHER_API_KEY is required")
+		}
+		if cfg.WeatherFixturesDir == "" {
+			return fmt.Errorf("WEATHER_FIXTURES_DIR is required")
+		}
+	default:
+		return fmt.Errorf("unknown provider %q, must be one of: weatherapi, openmeteo, openweathermap, replay, record", name)
+	}
+	return nil
+}
+
+// validateEmail checks that the email transport is known and, for SMTP, that it is configured
+func (cfg *Config) validateEmail() error {
+	switch cfg.EmailTransport {
+	case "smtp":
+	case "log":
+		// Emails are only logged, SMTP settings are not used
+		return nil
+	default:
+		return fmt.Errorf("EMAIL_TRANSPORT must be 'smtp' or 'log'")
+	}
+	if cfg.EmailClientFrom == "" {
+		return fmt.Errorf("SMTP_FROM is required")
+	}
+	if cfg.EmailClientPassword == "" {
+		return fmt.Errorf("SMTP_PASSWORD is required")
+	}
+	if cfg.EmailClientHost == "" {
+		return fmt.Errorf("SMTP_HOST is required")
+	}
+	if cfg.EmailClientPort == "" {
+		return fmt.Errorf("SMTP_PORT is required")
+	}
+	if cfg.EmailClientTimeout <= 0 || cfg.EmailClientTimeout >= cfg.SchedulerDeliveryLease {
+		return fmt.Errorf("SMTP_TIMEOUT must be positive and shorter than SCHEDULER_DELIVERY_LEASE")
+	}
+	return nil
+}
+
+// validateBudget checks that a call budget is set for a known provider and is not negative
+func (cfg *Config) validateBudget(name string, budget int64) error {
+	switch name {
+	case "weatherapi", "openmeteo", "openweathermap", "replay", "record":
+	default:
+		return fmt.Errorf("unknown provider %q, must be one of: weatherapi, openmeteo, openweathermap, replay, record", name)
+	}
+	if budget < 0 {
+		return fmt.Errorf("budget of %s must not be negative", name)
+	}
+	return nil
+}
+
 func (cfg *Config) GetDSN() string {
 	return fmt.Sprintf(
 		"postgres://%s:%s@%s:%d/%s?sslmode=disable",
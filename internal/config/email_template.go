@@ -1,12 +1,12 @@
 package config
 
-import "fmt"
+import "Weather-API-Application/internal/i18n"
 
-const ConfirmSubject = "Confirm your subscription"
+// ConfirmSubject returns the subject of the subscription confirmation email.
+func ConfirmSubject(lang i18n.Language) string {
+	return lang.Message(i18n.ConfirmSubject)
+}
 
-func BuildConfirmBody(baseURL, token string) string {
-	return fmt.Sprintf(
-		`<p>Click <a href="%s/api/subscription/confirm/%s">here</a> to confirm your subscription.</p>`,
-		baseURL, token,
-	)
+func BuildConfirmBody(baseURL, token string, lang i18n.Language) string {
+	return lang.Message(i18n.ConfirmBody, baseURL, token)
 }
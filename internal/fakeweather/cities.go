@@ -0,0 +1,43 @@
+package fakeweather
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// DefaultCities returns the cities served when none are configured.
+func DefaultCities() []City {
+	return []City{
+		{
+			ID: 2801268, Name: "Kyiv", Region: "Kyyiv", Country: "Ukraine", Lat: 50.43, Lon: 30.52, TzID: "Europe/Kyiv",
+			TempC: 11.3, FeelsLikeC: 9.8, Humidity: 71, WindKph: 13.7, WindDegree: 240, PressureMb: 1016,
+			Cloud: 50, VisKm: 10, UV: 2, Condition: "Partly cloudy",
+		},
+		{
+			ID: 2643741, Name: "London", Region: "City of London, Greater London", Country: "United Kingdom",
+			Lat: 51.52, Lon: -0.11, TzID: "Europe/London",
+			TempC: 13.0, FeelsLikeC: 11.6, Humidity: 82, WindKph: 19.1, WindDegree: 200, PressureMb: 1009,
+			PrecipMm: 0.4, Cloud: 100, VisKm: 9, UV: 1, Condition: "Light rain",
+		},
+		{
+			ID: 2618724, Name: "New York", Region: "New York", Country: "United States of America",
+			Lat: 40.71, Lon: -74.01, TzID: "America/New_York",
+			TempC: 18.9, FeelsLikeC: 18.9, Humidity: 55, WindKph: 9.4, WindDegree: 310, PressureMb: 1021,
+			Cloud: 0, VisKm: 16, UV: 4, Condition: "Sunny",
+		},
+	}
+}
+
+// LoadCities reads a JSON array of cities from a file.
+func LoadCities(path string) ([]City, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cities: %w", err)
+	}
+	var cities []City
+	if err := json.Unmarshal(data, &cities); err != nil {
+		return nil, fmt.Errorf("failed to decode cities: %w", err)
+	}
+	return cities, nil
+}
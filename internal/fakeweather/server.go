@@ -0,0 +1,372 @@
+// Package fakeweather emulates the WeatherAPI.com current.json, forecast.json, search.json and
+// timezone.json endpoints with configurable per-city data and injected latency, errors and malformed JSON.
+// It is used by integration tests and by the fakeweather command.
+package fakeweather
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WeatherAPI.com error codes returned by the server.
+const (
+	CodeMissingQuery  = 1003
+	CodeNoLocation    = 1006
+	CodeInvalidKey    = 2006
+	CodeQuotaExceeded = 2007
+	CodeInternal      = 9999
+
+	// maxForecastDays is the longest forecast WeatherAPI.com returns.
+	maxForecastDays = 14
+	// coordinateTolerance is how far, in degrees, a "lat,lon" query may be from a city to match it.
+	coordinateTolerance = 0.5
+)
+
+// Fault is injected into responses instead of, or before, the normal data.
+type Fault struct {
+	// Latency delays the response, e.g. "2s" in JSON.
+	Latency Duration `json:"latency,omitempty"`
+	// Status, when set, makes the server respond with this HTTP status and a WeatherAPI.com error body.
+	Status int `json:"status,omitempty"`
+	// Code is the WeatherAPI.com error code sent with Status, CodeInternal by default.
+	Code int `json:"code,omitempty"`
+	// Malformed makes the server respond 200 OK with a truncated JSON body.
+	Malformed bool `json:"malformed,omitempty"`
+}
+
+func (f Fault) isZero() bool {
+	return f == Fault{}
+}
+
+// Duration is a time.Duration written as a string such as "1.5s" in JSON.
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"2s\": %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// City is a location known to the server with its current weather.
+type City struct {
+	ID         int64   `json:"id"`
+	Name       string  `json:"name"`
+	Region     string  `json:"region"`
+	Country    string  `json:"country"`
+	Lat        float64 `json:"lat"`
+	Lon        float64 `json:"lon"`
+	TzID       string  `json:"tz_id"`
+	TempC      float64 `json:"temp_c"`
+	FeelsLikeC float64 `json:"feelslike_c"`
+	Humidity   float64 `json:"humidity"`
+	WindKph    float64 `json:"wind_kph"`
+	WindDegree int     `json:"wind_degree"`
+	PressureMb float64 `json:"pressure_mb"`
+	PrecipMm   float64 `json:"precip_mm"`
+	Cloud      int     `json:"cloud"`
+	VisKm      float64 `json:"vis_km"`
+	UV         float64 `json:"uv"`
+	Condition  string  `json:"condition"`
+	// Fault is injected into current, forecast and timezone requests for this city instead of Config.Fault.
+	Fault Fault `json:"fault,omitempty"`
+}
+
+// Config configures the server.
+type Config struct {
+	// APIKey, when set, is required as the key parameter of every request.
+	APIKey string
+	// Cities are the locations the server knows, DefaultCities when empty.
+	Cities []City
+	// Fault is injected into every request, except current, forecast and timezone requests for cities with their own fault.
+	Fault Fault
+}
+
+// Server is an http.Handler serving the WeatherAPI.com API under /v1.
+type Server struct {
+	cfg Config
+	mux *http.ServeMux
+}
+
+func NewServer(cfg Config) *Server {
+	if len(cfg.Cities) == 0 {
+		cfg.Cities = DefaultCities()
+	}
+	s := &Server{cfg: cfg, mux: http.NewServeMux()}
+	s.mux.HandleFunc("GET /v1/current.json", s.current)
+	s.mux.HandleFunc("GET /v1/forecast.json", s.forecast)
+	s.mux.HandleFunc("GET /v1/search.json", s.search)
+	s.mux.HandleFunc("GET /v1/timezone.json", s.timezone)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) current(w http.ResponseWriter, r *http.Request) {
+	city, ok := s.city(w, r)
+	if !ok {
+		return
+	}
+
+	body := map[string]any{
+		"location": location(city),
+		"current":  current(city, r.URL.Query().Get("aqi") == "yes"),
+	}
+	writeJSON(w, http.StatusOK, body)
+}
+
+func (s *Server) forecast(w http.ResponseWriter, r *http.Request) {
+	city, ok := s.city(w, r)
+	if !ok {
+		return
+	}
+
+	days, err := strconv.Atoi(r.URL.Query().Get("days"))
+	if err != nil || days < 1 {
+		days = 1
+	}
+	days = min(days, maxForecastDays)
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	forecastDays := make([]map[string]any, 0, days)
+	for i := range days {
+		forecastDays = append(forecastDays, forecastDay(city, today.AddDate(0, 0, i)))
+	}
+
+	body := map[string]any{
+		"location": location(city),
+		"current":  current(city, r.URL.Query().Get("aqi") == "yes"),
+		"forecast": map[string]any{"forecastday": forecastDays},
+		"alerts":   map[string]any{"alert": []any{}},
+	}
+	writeJSON(w, http.StatusOK, body)
+}
+
+func (s *Server) search(w http.ResponseWriter, r *http.Request) {
+	query, ok := s.query(w, r)
+	if !ok || !s.inject(w, r, s.cfg.Fault) {
+		return
+	}
+
+	results := []map[string]any{}
+	for _, city := range s.cfg.Cities {
+		if strings.Contains(strings.ToLower(city.Name), query) {
+			results = append(results, map[string]any{
+				"id":      city.ID,
+				"name":    city.Name,
+				"region":  city.Region,
+				"country": city.Country,
+				"lat":     city.Lat,
+				"lon":     city.Lon,
+			})
+		}
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
+func (s *Server) timezone(w http.ResponseWriter, r *http.Request) {
+	city, ok := s.city(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"location": location(city)})
+}
+
+// city checks the request, finds the queried city and injects its fault.
+// It returns false when a response has already been written.
+func (s *Server) city(w http.ResponseWriter, r *http.Request) (City, bool) {
+	query, ok := s.query(w, r)
+	if !ok {
+		return City{}, false
+	}
+
+	city, ok := s.lookup(query)
+	if !ok {
+		writeError(w, http.StatusBadRequest, CodeNoLocation, "No matching location found.")
+		return City{}, false
+	}
+
+	fault := s.cfg.Fault
+	if !city.Fault.isZero() {
+		fault = city.Fault
+	}
+	return city, s.inject(w, r, fault)
+}
+
+// query checks the API key and returns the lower-cased q parameter.
+func (s *Server) query(w http.ResponseWriter, r *http.Request) (string, bool) {
+	params := r.URL.Query()
+	if s.cfg.APIKey != "" && params.Get("key") != s.cfg.APIKey {
+		writeError(w, http.StatusUnauthorized, CodeInvalidKey, "API key provided is invalid.")
+		return "", false
+	}
+	query := strings.ToLower(strings.TrimSpace(params.Get("q")))
+	if query == "" {
+		writeError(w, http.StatusBadRequest, CodeMissingQuery, "Parameter q is missing.")
+		return "", false
+	}
+	return query, true
+}
+
+// lookup finds a city by name, "id:<id>" or "lat,lon" within coordinateTolerance.
+func (s *Server) lookup(query string) (City, bool) {
+	if id, ok := strings.CutPrefix(query, "id:"); ok {
+		for _, city := range s.cfg.Cities {
+			if strconv.FormatInt(city.ID, 10) == id {
+				return city, true
+			}
+		}
+		return City{}, false
+	}
+
+	if latStr, lonStr, ok := strings.Cut(query, ","); ok {
+		lat, latErr := strconv.ParseFloat(strings.TrimSpace(latStr), 64)
+		lon, lonErr := strconv.ParseFloat(strings.TrimSpace(lonStr), 64)
+		if latErr == nil && lonErr == nil {
+			for _, city := range s.cfg.Cities {
+				if math.Abs(city.Lat-lat) <= coordinateTolerance && math.Abs(city.Lon-lon) <= coordinateTolerance {
+					return city, true
+				}
+			}
+			return City{}, false
+		}
+	}
+
+	for _, city := range s.cfg.Cities {
+		if strings.ToLower(city.Name) == query {
+			return city, true
+		}
+	}
+	return City{}, false
+}
+
+// inject applies the fault. It returns false when the fault replaced the response.
+func (s *Server) inject(w http.ResponseWriter, r *http.Request, fault Fault) bool {
+	if fault.Latency > 0 {
+		timer := time.NewTimer(time.Duration(fault.Latency))
+		defer timer.Stop()
+		select {
+		case <-r.Context().Done():
+			return false
+		case <-timer.C:
+		}
+	}
+
+	if fault.Status != 0 {
+		code := fault.Code
+		if code == 0 {
+			code = CodeInternal
+		}
+		writeError(w, fault.Status, code, "Injected failure.")
+		return false
+	}
+
+	if fault.Malformed {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"location":{"name":`))
+		return false
+	}
+	return true
+}
+
+func location(city City) map[string]any {
+	return map[string]any{
+		"name":    city.Name,
+		"region":  city.Region,
+		"country": city.Country,
+		"lat":     city.Lat,
+		"lon":     city.Lon,
+		"tz_id":   city.TzID,
+	}
+}
+
+func current(city City, airQuality bool) map[string]any {
+	body := map[string]any{
+		"last_updated_epoch": time.Now().Truncate(15 * time.Minute).Unix(),
+		"temp_c":             city.TempC,
+		"feelslike_c":        city.FeelsLikeC,
+		"humidity":           city.Humidity,
+		"wind_kph":           city.WindKph,
+		"wind_degree":        city.WindDegree,
+		"gust_kph":           math.Round(city.WindKph*1.5*10) / 10,
+		"pressure_mb":        city.PressureMb,
+		"precip_mm":          city.PrecipMm,
+		"cloud":              city.Cloud,
+		"vis_km":             city.VisKm,
+		"uv":                 city.UV,
+		"condition":          map[string]any{"text": city.Condition},
+	}
+	if airQuality {
+		body["air_quality"] = map[string]any{
+			"pm2_5":          8.1,
+			"pm10":           11.5,
+			"o3":             61.0,
+			"no2":            12.6,
+			"us-epa-index":   1,
+			"gb-defra-index": 1,
+		}
+	}
+	return body
+}
+
+// forecastDay builds a day around the city's current temperature, warmest in the afternoon.
+func forecastDay(city City, date time.Time) map[string]any {
+	hours := make([]map[string]any, 0, 24)
+	minTemp, maxTemp, sumTemp := math.Inf(1), math.Inf(-1), 0.0
+	for h := range 24 {
+		temp := math.Round((city.TempC+4*math.Sin(float64(h-9)/24*2*math.Pi))*10) / 10
+		minTemp, maxTemp, sumTemp = math.Min(minTemp, temp), math.Max(maxTemp, temp), sumTemp+temp
+		hours = append(hours, map[string]any{
+			"time_epoch":     date.Add(time.Duration(h) * time.Hour).Unix(),
+			"temp_c":         temp,
+			"humidity":       city.Humidity,
+			"precip_mm":      city.PrecipMm,
+			"chance_of_rain": 0,
+			"condition":      map[string]any{"text": city.Condition},
+		})
+	}
+
+	return map[string]any{
+		"date": date.Format(time.DateOnly),
+		"day": map[string]any{
+			"maxtemp_c":            maxTemp,
+			"mintemp_c":            minTemp,
+			"avgtemp_c":            math.Round(sumTemp/24*10) / 10,
+			"avghumidity":          city.Humidity,
+			"maxwind_kph":          city.WindKph,
+			"daily_chance_of_rain": 0,
+			"condition":            map[string]any{"text": city.Condition},
+		},
+		"hour": hours,
+	}
+}
+
+func writeError(w http.ResponseWriter, status, code int, message string) {
+	writeJSON(w, status, map[string]any{
+		"error": map[string]any{"code": code, "message": message},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,113 @@
+package fakeweather
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookup(t *testing.T) {
+	s := NewServer(Config{})
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"kyiv", "Kyiv"},
+		{"new york", "New York"},
+		{"id:2643741", "London"},
+		{"id:1", ""},
+		{"50.43,30.52", "Kyiv"},
+		{"50.9,30.1", "Kyiv"},
+		{" 51.52 , -0.11 ", "London"},
+		{"51.1,30.52", ""},
+		{"0,0", ""},
+		{"kyi", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			city, ok := s.lookup(tt.query)
+			require.Equal(t, tt.want != "", ok)
+			require.Equal(t, tt.want, city.Name)
+		})
+	}
+}
+
+func TestFaults(t *testing.T) {
+	kyiv, london := DefaultCities()[0], DefaultCities()[1]
+	kyiv.Fault = Fault{Status: http.StatusServiceUnavailable}
+	s := NewServer(Config{
+		Cities: []City{kyiv, london},
+		Fault:  Fault{Status: http.StatusForbidden, Code: CodeQuotaExceeded},
+	})
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantCode   int
+	}{
+		{"city fault replaces the server fault", "/v1/current.json?q=Kyiv", http.StatusServiceUnavailable, CodeInternal},
+		{"city fault on timezone", "/v1/timezone.json?q=id:2801268", http.StatusServiceUnavailable, CodeInternal},
+		{"server fault for cities without one", "/v1/forecast.json?q=London", http.StatusForbidden, CodeQuotaExceeded},
+		{"server fault on search", "/v1/search.json?q=kyiv", http.StatusForbidden, CodeQuotaExceeded},
+		{"unknown city before faults", "/v1/current.json?q=Paris", http.StatusBadRequest, CodeNoLocation},
+		{"missing query before faults", "/v1/current.json", http.StatusBadRequest, CodeMissingQuery},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			require.Equal(t, tt.wantStatus, rec.Code)
+
+			var body struct {
+				Error struct {
+					Code int `json:"code"`
+				} `json:"error"`
+			}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			require.Equal(t, tt.wantCode, body.Error.Code)
+		})
+	}
+}
+
+func TestMalformed(t *testing.T) {
+	s := NewServer(Config{Fault: Fault{Malformed: true}})
+
+	for _, path := range []string{"/v1/current.json?q=Kyiv", "/v1/forecast.json?q=Kyiv", "/v1/search.json?q=Kyiv", "/v1/timezone.json?q=Kyiv"} {
+		t.Run(path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+			require.Equal(t, http.StatusOK, rec.Code)
+			require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+			var body map[string]any
+			require.Error(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		})
+	}
+}
+
+func TestTimezone(t *testing.T) {
+	s := NewServer(Config{APIKey: "secret"})
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/timezone.json?key=secret&q=id:2618724", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var body struct {
+		Location struct {
+			Name string `json:"name"`
+			TzID string `json:"tz_id"`
+		} `json:"location"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Equal(t, "New York", body.Location.Name)
+	require.Equal(t, "America/New_York", body.Location.TzID)
+
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/timezone.json?key=wrong&q=id:2618724", nil))
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"net/http"
+
+	"Weather-API-Application/internal/config"
+	"Weather-API-Application/internal/quota"
+	"Weather-API-Application/internal/resilience"
+	"Weather-API-Application/internal/utils/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StatusHandler struct {
+	cfg      *config.Config
+	breakers *resilience.Registry
+	meter    *quota.Meter
+}
+
+func NewStatusHandler(cfg *config.Config, breakers *resilience.Registry, meter *quota.Meter) *StatusHandler {
+	return &StatusHandler{cfg: cfg, breakers: breakers, meter: meter}
+}
+
+// RegisterRoutes registers monitoring endpoints.
+func (h *StatusHandler) RegisterRoutes(router *gin.Engine) {
+	api := router.Group("/api")
+	{
+		api.GET("/status/providers", h.GetProviderStatus)
+		api.GET("/status/usage", h.GetUsage)
+	}
+}
+
+// GetProviderStatus godoc
+// @Summary      Get weather provider circuit breaker states
+// @Description  Returns the circuit breaker of each upstream weather provider: closed (healthy), open (calls rejected until retry_after_seconds pass) or half-open (a probe call is deciding).
+// @Tags         status
+// @Produce      json
+// @Success      200  {array}  resilience.Status  "Circuit breaker states, in provider priority order"
+// @Router       /status/providers [get]
+func (h *StatusHandler) GetProviderStatus(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, h.breakers.Statuses())
+}
+
+// GetUsage godoc
+// @Summary      Get weather provider API consumption
+// @Description  Returns the outbound calls made to each configured weather provider in the current UTC day and month, with the configured budgets.
+// @Tags         status
+// @Produce      json
+// @Success      200  {array}   model.ProviderUsage     "Usage per provider, in priority order"
+// @Failure      500  {object}  response.ErrorResponse  "Internal server error"
+// @Router       /status/usage [get]
+func (h *StatusHandler) GetUsage(ctx *gin.Context) {
+	names := append([]string{h.cfg.WeatherProvider}, h.cfg.WeatherFallbackProviders...)
+	usage, err := h.meter.Report(ctx.Request.Context(), names)
+	if err != nil {
+		response.WriteErrorJSON(ctx, http.StatusInternalServerError, err, "Internal server error")
+		return
+	}
+	ctx.JSON(http.StatusOK, usage)
+}
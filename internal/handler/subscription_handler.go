@@ -4,11 +4,14 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"Weather-API-Application/internal/config"
+	"Weather-API-Application/internal/i18n"
 	"Weather-API-Application/internal/model"
 	"Weather-API-Application/internal/services/subscription_service"
 	"Weather-API-Application/internal/utils/response"
+	"Weather-API-Application/internal/utils/units"
 	"Weather-API-Application/internal/utils/validate"
 
 	"github.com/gin-gonic/gin"
@@ -38,13 +41,14 @@ func (h *SubscriptionHandler) RegisterRoutes(router *gin.Engine) {
 
 // Subscribe godoc
 // @Summary      Subscribe to weather updates
-// @Description  Subscribes an email to weather updates for a city with a frequency.
+// @Description  Subscribes an email to weather updates for a city, or for a latitude/longitude pair, with a frequency. The "alerts" frequency only sends emails when a new severe weather alert is issued.
 // @Tags         subscription
 // @Accept       json
 // @Produce      json
-// @Param        subscription  body   model.Subscription  true  "Subscription request"
+// @Param        subscription     body    model.Subscription  true   "Subscription request"
+// @Param        Accept-Language  header  string              false  "Email language when the request has none"
 // @Success      200  {object}  model.Subscription  "Subscription request accepted. Confirmation email sent."
-// @Failure      400  {object}  response.ErrorResponse  "Invalid input"
+// @Failure      400  {object}  response.ErrorResponse  "Invalid input or city not found"
 // @Failure      409  {object}  response.ErrorResponse  "Email already subscribed"
 // @Failure      500  {object}  response.ErrorResponse  "Internal error"
 // @Router       /subscription/subscribe [post]
@@ -62,24 +66,57 @@ func (h *SubscriptionHandler) Subscribe(ctx *gin.Context) {
 			"Invalid email format")
 		return
 	}
-	if !validate.IsValidCity(req.City) {
+	if req.Latitude != nil || req.Longitude != nil {
+		if req.Latitude == nil || req.Longitude == nil ||
+			!validate.IsValidLatitude(*req.Latitude) || !validate.IsValidLongitude(*req.Longitude) {
+			response.WriteErrorJSON(ctx, http.StatusBadRequest,
+				fmt.Errorf("invalid coordinates"),
+				"Latitude must be between -90 and 90 and longitude between -180 and 180")
+			return
+		}
+		// Coordinates take precedence over the city name
+		req.City = model.Coordinates{Lat: *req.Latitude, Lon: *req.Longitude}.String()
+	} else if !validate.IsValidCity(req.City) {
 		response.WriteErrorJSON(ctx, http.StatusBadRequest,
 			fmt.Errorf("invalid city"),
-			"City is required and cannot be empty")
+			"City or latitude/longitude is required")
 		return
 	}
+	req.Frequency = strings.ToLower(strings.TrimSpace(req.Frequency))
 	if !validate.IsValidFrequency(req.Frequency) {
 		response.WriteErrorJSON(ctx, http.StatusBadRequest,
 			fmt.Errorf("invalid frequency"),
-			"Frequency must be 'hourly' or 'daily'")
+			"Frequency must be 'hourly', 'daily' or 'alerts'")
+		return
+	}
+	system, ok := units.Parse(req.Units)
+	if !ok {
+		response.WriteErrorJSON(ctx, http.StatusBadRequest,
+			fmt.Errorf("invalid units"),
+			"Units must be 'metric', 'imperial' or 'scientific'")
 		return
 	}
+	req.Units = string(system)
+	// The language defaults to the one preferred by the browser
+	lang := i18n.Negotiate(ctx.GetHeader("Accept-Language"))
+	if req.Language != "" {
+		if lang, ok = i18n.Parse(req.Language); !ok {
+			response.WriteErrorJSON(ctx, http.StatusBadRequest,
+				fmt.Errorf("invalid language"),
+				"Language must be 'en' or 'uk'")
+			return
+		}
+	}
+	req.Language = string(lang)
 
 	if err := h.subscriptionService.Subscribe(ctx.Request.Context(), &req); err != nil {
 		switch {
 		case errors.Is(err, subscription_service.ErrSubscriptionExists):
 			response.WriteErrorJSON(ctx, http.StatusConflict, err, "Email already subscribed")
 			return
+		case errors.Is(err, subscription_service.ErrLocationNotFound):
+			response.WriteErrorJSON(ctx, http.StatusBadRequest, err, "City not found")
+			return
 		default:
 			response.WriteErrorJSON(ctx, http.StatusInternalServerError, err, "Internal server error")
 			return
@@ -106,7 +143,7 @@ func (h *SubscriptionHandler) ConfirmSubscription(ctx *gin.Context) {
 	if err != nil {
 		switch {
 		case errors.Is(err, subscription_service.ErrNotFound):
-            response.WriteErrorJSON(ctx, http.StatusNotFound, err, "Token not found")
+			response.WriteErrorJSON(ctx, http.StatusNotFound, err, "Token not found")
 			return
 		case errors.Is(err, subscription_service.ErrAlreadyConfirmed):
 			response.WriteErrorJSON(ctx, http.StatusBadRequest, err, "Already confirmed")
@@ -1,22 +1,38 @@
 package handler
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"Weather-API-Application/internal/config"
+	"Weather-API-Application/internal/i18n"
+	"Weather-API-Application/internal/model"
+	"Weather-API-Application/internal/resilience"
 	"Weather-API-Application/internal/services/weather_service"
+	"Weather-API-Application/internal/utils/fields"
 	"Weather-API-Application/internal/utils/response"
+	"Weather-API-Application/internal/utils/units"
 	"Weather-API-Application/internal/utils/validate"
 
 	"github.com/gin-gonic/gin"
 )
 
+// weatherFields are the fields of the current weather that can be selected with the fields parameter.
+var weatherFields = fields.Names(model.Weather{})
+
 type WeatherHandler struct {
+	cfg *config.Config
 	svc weather_service.WeatherService
 }
 
-func NewWeatherHandler(svc weather_service.WeatherService) *WeatherHandler {
-	return &WeatherHandler{svc: svc}
+func NewWeatherHandler(cfg *config.Config, svc weather_service.WeatherService) *WeatherHandler {
+	return &WeatherHandler{cfg: cfg, svc: svc}
 }
 
 // RegisterRoutes registers weather endpoints.
@@ -24,42 +40,577 @@ func (h *WeatherHandler) RegisterRoutes(router *gin.Engine) {
 	api := router.Group("/api")
 	{
 		api.GET("/weather", h.GetWeather)
+		api.POST("/weather/batch", h.GetWeatherBatch)
+		api.GET("/forecast", h.GetForecast)
+		api.GET("/forecast/hourly", h.GetHourlyForecast)
+		api.GET("/history", h.GetHistory)
+		api.GET("/alerts", h.GetAlerts)
+		api.GET("/astronomy", h.GetAstronomy)
+		api.GET("/cities/search", h.SearchCities)
 	}
 }
 
 // GetWeather godoc
 // @Summary      Get current weather for a city
-// @Description  Returns the current weather for the specified city using WeatherAPI.com.
+// @Description  Returns the current weather for the specified city or coordinates using the configured weather provider.
 // @Tags         weather
 // @Accept       json
 // @Produce      json
-// @Param        city  query     string  true  "City name"
+// @Param        city     query     string  false  "City name, required unless lat and lon are given"
+// @Param        lat      query     number  false  "Latitude, -90 to 90"
+// @Param        lon      query     number  false  "Longitude, -180 to 180"
+// @Param        include  query     string  false  "Comma-separated extra data to include"  Enums(aqi)
+// @Param        fields   query     string  false  "Comma-separated response fields to return, e.g. temperature,wind_speed,uv_index (units is always returned)"
+// @Param        units    query     string  false  "Unit system"  Enums(metric,imperial,scientific)  default(metric)
+// @Param        lang     query     string  false  "Language of descriptions, overrides Accept-Language"  Enums(en,uk)
+// @Param        Accept-Language  header  string  false  "Preferred language of descriptions"
 // @Success      200   {object}  model.Weather  "Current weather returned"
+// @Header       200   {string}  X-Cache  "HIT, MISS or STALE"
+// @Header       200   {integer} Age      "Age of the cached response in seconds"
 // @Failure      400   {object}  response.ErrorResponse   "Invalid request"
 // @Failure      404   {object}  response.ErrorResponse   "City not found"
+// @Failure      502   {object}  response.ErrorResponse   "Weather provider unavailable"
+// @Failure      503   {object}  response.ErrorResponse   "Weather provider quota exceeded or temporarily unavailable"
+// @Header       503   {integer} Retry-After  "Seconds until the weather provider is retried"
 // @Router       /weather [get]
 func (h *WeatherHandler) GetWeather(ctx *gin.Context) {
-	city := ctx.Query("city")
+	// Validate input
+	city, ok := locationQuery(ctx)
+	if !ok {
+		return
+	}
+	include := ctx.Query("include")
+	if !validate.IsValidInclude(include) {
+		response.WriteErrorJSON(ctx, http.StatusBadRequest,
+			fmt.Errorf("invalid include parameter: %q", include),
+			"Include must be a comma-separated list of: aqi")
+		return
+	}
+	selected, err := fields.Parse(ctx.Query("fields"), weatherFields)
+	if err != nil {
+		response.WriteErrorJSON(ctx, http.StatusBadRequest, err,
+			"Fields must be a comma-separated list of: "+strings.Join(weatherFields, ", "))
+		return
+	}
+	system, ok := unitsQuery(ctx)
+	if !ok {
+		return
+	}
+	lang, ok := languageQuery(ctx)
+	if !ok {
+		return
+	}
+
+	fetchedWeather, err := h.svc.FetchWeatherForCity(ctx.Request.Context(), city)
+	if err != nil {
+		writeServiceError(ctx, err)
+		return
+	}
+	// aqi is currently the only supported include value
+	if strings.TrimSpace(include) != "" {
+		airQuality, err := h.svc.FetchAirQuality(ctx.Request.Context(), city)
+		if err != nil {
+			writeServiceError(ctx, err)
+			return
+		}
+		fetchedWeather.AirQuality = airQuality
+	}
+	units.ConvertWeather(fetchedWeather, system)
+	i18n.LocalizeWeather(fetchedWeather, lang)
+	setCacheHeaders(ctx, fetchedWeather.Cache)
+	if selected == nil {
+		ctx.JSON(200, fetchedWeather)
+		return
+	}
+
+	// Units are kept so that the selected values can be interpreted
+	sparse, err := fields.Select(fetchedWeather, append(selected, "units"))
+	if err != nil {
+		response.WriteErrorJSON(ctx, http.StatusInternalServerError, err, "Internal server error")
+		return
+	}
+	ctx.JSON(200, sparse)
+}
+
+// GetWeatherBatch godoc
+// @Summary      Get current weather for several locations
+// @Description  Returns the current weather for up to WEATHER_BATCH_MAX_ITEMS cities or coordinates, fetched concurrently.
+// @Description  Results are in request order; each has the status and error GET /weather would return for that location.
+// @Tags         weather
+// @Accept       json
+// @Produce      json
+// @Param        body   body      model.BatchWeatherRequest  true   "Locations to look up"
+// @Param        units  query     string  false  "Unit system"  Enums(metric,imperial,scientific)  default(metric)
+// @Param        lang   query     string  false  "Language of descriptions, overrides Accept-Language"  Enums(en,uk)
+// @Param        Accept-Language  header  string  false  "Preferred language of descriptions"
+// @Success      200   {object}  model.BatchWeatherResponse  "Per-location results returned"
+// @Failure      400   {object}  response.ErrorResponse      "Invalid request"
+// @Router       /weather/batch [post]
+func (h *WeatherHandler) GetWeatherBatch(ctx *gin.Context) {
+	// Validate input
+	var req model.BatchWeatherRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.WriteErrorJSON(ctx, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+	if len(req.Locations) == 0 || len(req.Locations) > h.cfg.WeatherBatchMaxItems {
+		response.WriteErrorJSON(ctx, http.StatusBadRequest,
+			fmt.Errorf("invalid number of locations: %d", len(req.Locations)),
+			fmt.Sprintf("Locations must contain 1 to %d items", h.cfg.WeatherBatchMaxItems))
+		return
+	}
+	system, ok := unitsQuery(ctx)
+	if !ok {
+		return
+	}
+	lang, ok := languageQuery(ctx)
+	if !ok {
+		return
+	}
+
+	// Invalid items get their own 400 result; the rest are fetched in one batch
+	results := make([]model.BatchWeatherResult, len(req.Locations))
+	var cities []string
+	var positions []int
+	for i, loc := range req.Locations {
+		city, err := batchLocation(loc)
+		results[i].Location = city
+		if err != nil {
+			results[i].Status = http.StatusBadRequest
+			results[i].Error = err.Error()
+			continue
+		}
+		cities = append(cities, city)
+		positions = append(positions, i)
+	}
+
+	for i, res := range h.svc.FetchWeatherForCities(ctx.Request.Context(), cities) {
+		result := &results[positions[i]]
+		if res.Err != nil {
+			ctx.Error(res.Err)
+			result.Status, result.Error = serviceErrorResponse(res.Err)
+			continue
+		}
+		units.ConvertWeather(res.Weather, system)
+		i18n.LocalizeWeather(res.Weather, lang)
+		result.Status = http.StatusOK
+		result.Weather = res.Weather
+	}
+	ctx.JSON(http.StatusOK, model.BatchWeatherResponse{Results: results})
+}
+
+// GetForecast godoc
+// @Summary      Get daily forecast for a city
+// @Description  Returns daily min/max temperature, chance of rain, condition and max wind speed for up to 14 days.
+// @Tags         weather
+// @Accept       json
+// @Produce      json
+// @Param        city  query     string   false  "City name, required unless lat and lon are given"
+// @Param        lat   query     number   false  "Latitude, -90 to 90"
+// @Param        lon   query     number   false  "Longitude, -180 to 180"
+// @Param        days  query     integer  false  "Number of days (1-14)"  default(3)
+// @Param        units query     string   false  "Unit system"  Enums(metric,imperial,scientific)  default(metric)
+// @Param        lang  query     string  false  "Language of descriptions, overrides Accept-Language"  Enums(en,uk)
+// @Param        Accept-Language  header  string  false  "Preferred language of descriptions"
+// @Success      200   {object}  model.Forecast  "Forecast returned"
+// @Failure      400   {object}  response.ErrorResponse   "Invalid request"
+// @Failure      404   {object}  response.ErrorResponse   "City not found"
+// @Failure      502   {object}  response.ErrorResponse   "Weather provider unavailable"
+// @Failure      503   {object}  response.ErrorResponse   "Weather provider quota exceeded or temporarily unavailable"
+// @Header       503   {integer} Retry-After  "Seconds until the weather provider is retried"
+// @Router       /forecast [get]
+func (h *WeatherHandler) GetForecast(ctx *gin.Context) {
+	// Validate input
+	city, ok := locationQuery(ctx)
+	if !ok {
+		return
+	}
+	days, err := strconv.Atoi(ctx.DefaultQuery("days", "3"))
+	if err != nil || !validate.IsValidForecastDays(days) {
+		response.WriteErrorJSON(ctx, http.StatusBadRequest,
+			fmt.Errorf("invalid days parameter: %q", ctx.Query("days")),
+			fmt.Sprintf("Days must be a number between 1 and %d", validate.MaxForecastDays))
+		return
+	}
+	system, ok := unitsQuery(ctx)
+	if !ok {
+		return
+	}
+	lang, ok := languageQuery(ctx)
+	if !ok {
+		return
+	}
+
+	forecast, err := h.svc.FetchForecast(ctx.Request.Context(), city, days)
+	if err != nil {
+		writeServiceError(ctx, err)
+		return
+	}
+	units.ConvertForecast(forecast, system)
+	i18n.LocalizeForecast(forecast, lang)
+	ctx.JSON(http.StatusOK, forecast)
+}
+
+// GetHourlyForecast godoc
+// @Summary      Get hourly forecast for a city
+// @Description  Returns hourly temperature, precipitation and condition points between from and to (at most 48 hours). Defaults to the next 24 hours.
+// @Tags         weather
+// @Accept       json
+// @Produce      json
+// @Param        city  query     string  false  "City name, required unless lat and lon are given"
+// @Param        lat   query     number  false  "Latitude, -90 to 90"
+// @Param        lon   query     number  false  "Longitude, -180 to 180"
+// @Param        from  query     string  false  "Window start, RFC3339 (default: now)"
+// @Param        to    query     string  false  "Window end, RFC3339 (default: from + 24h)"
+// @Param        units query     string  false  "Unit system"  Enums(metric,imperial,scientific)  default(metric)
+// @Param        lang  query     string  false  "Language of descriptions, overrides Accept-Language"  Enums(en,uk)
+// @Param        Accept-Language  header  string  false  "Preferred language of descriptions"
+// @Success      200   {object}  model.HourlyForecast  "Hourly forecast returned"
+// @Failure      400   {object}  response.ErrorResponse   "Invalid request"
+// @Failure      404   {object}  response.ErrorResponse   "City not found"
+// @Failure      502   {object}  response.ErrorResponse   "Weather provider unavailable"
+// @Failure      503   {object}  response.ErrorResponse   "Weather provider quota exceeded or temporarily unavailable"
+// @Header       503   {integer} Retry-After  "Seconds until the weather provider is retried"
+// @Router       /forecast/hourly [get]
+func (h *WeatherHandler) GetHourlyForecast(ctx *gin.Context) {
+	// Validate input
+	city, ok := locationQuery(ctx)
+	if !ok {
+		return
+	}
+	from, err := parseTimeQuery(ctx, "from", time.Now().UTC().Truncate(time.Hour))
+	if err != nil {
+		response.WriteErrorJSON(ctx, http.StatusBadRequest, err, "From must be an RFC3339 timestamp")
+		return
+	}
+	to, err := parseTimeQuery(ctx, "to", from.Add(24*time.Hour))
+	if err != nil {
+		response.WriteErrorJSON(ctx, http.StatusBadRequest, err, "To must be an RFC3339 timestamp")
+		return
+	}
+	if !validate.IsValidHourlyWindow(from, to) {
+		response.WriteErrorJSON(ctx, http.StatusBadRequest,
+			fmt.Errorf("invalid time window: %s - %s", from, to),
+			fmt.Sprintf("To must be after from and the window must not exceed %d hours", int(validate.MaxHourlyWindow.Hours())))
+		return
+	}
+	system, ok := unitsQuery(ctx)
+	if !ok {
+		return
+	}
+	lang, ok := languageQuery(ctx)
+	if !ok {
+		return
+	}
+
+	forecast, err := h.svc.FetchHourlyForecast(ctx.Request.Context(), city, from, to)
+	if err != nil {
+		writeServiceError(ctx, err)
+		return
+	}
+	units.ConvertHourlyForecast(forecast, system)
+	i18n.LocalizeHourlyForecast(forecast, lang)
+	ctx.JSON(http.StatusOK, forecast)
+}
 
+// GetHistory godoc
+// @Summary      Get historical weather for a city
+// @Description  Returns observed conditions for a past date, from locally stored observations when available or from the weather provider's history API.
+// @Tags         weather
+// @Accept       json
+// @Produce      json
+// @Param        city  query     string  false  "City name, required unless lat and lon are given"
+// @Param        lat   query     number  false  "Latitude, -90 to 90"
+// @Param        lon   query     number  false  "Longitude, -180 to 180"
+// @Param        date  query     string  true  "Past date in YYYY-MM-DD format"
+// @Param        units query     string  false  "Unit system"  Enums(metric,imperial,scientific)  default(metric)
+// @Param        lang  query     string  false  "Language of descriptions, overrides Accept-Language"  Enums(en,uk)
+// @Param        Accept-Language  header  string  false  "Preferred language of descriptions"
+// @Success      200   {object}  model.History  "Historical weather returned"
+// @Failure      400   {object}  response.ErrorResponse   "Invalid request"
+// @Failure      404   {object}  response.ErrorResponse   "City or data not found"
+// @Failure      502   {object}  response.ErrorResponse   "Weather provider unavailable"
+// @Failure      503   {object}  response.ErrorResponse   "Weather provider quota exceeded or temporarily unavailable"
+// @Header       503   {integer} Retry-After  "Seconds until the weather provider is retried"
+// @Router       /history [get]
+func (h *WeatherHandler) GetHistory(ctx *gin.Context) {
 	// Validate input
-	if !validate.IsValidCity(city) {
+	city, ok := locationQuery(ctx)
+	if !ok {
+		return
+	}
+	date, err := time.Parse(time.DateOnly, ctx.Query("date"))
+	if err != nil || !validate.IsValidHistoryDate(date, time.Now()) {
 		response.WriteErrorJSON(ctx, http.StatusBadRequest,
-			fmt.Errorf("invalid city parameter"),
-			"City parameter is required and cannot be empty")
+			fmt.Errorf("invalid date parameter: %q", ctx.Query("date")),
+			"Date must be a past date in YYYY-MM-DD format")
+		return
+	}
+	system, ok := unitsQuery(ctx)
+	if !ok {
+		return
+	}
+	lang, ok := languageQuery(ctx)
+	if !ok {
 		return
 	}
 
-	fetchedWeather, err, code := h.svc.FetchWeatherForCity(city)
+	history, err := h.svc.FetchHistory(ctx.Request.Context(), city, date)
 	if err != nil {
-		msg := "Internal server error"
-		switch code {
-		case http.StatusNotFound:
-			msg = "City not found"
-		case http.StatusBadRequest:
-			msg = "Invalid request"
+		writeServiceError(ctx, err)
+		return
+	}
+	units.ConvertHistory(history, system)
+	i18n.LocalizeHistory(history, lang)
+	ctx.JSON(http.StatusOK, history)
+}
+
+// GetAlerts godoc
+// @Summary      Get severe weather alerts for a city
+// @Description  Returns active government weather alerts with headline, severity and effective/expiry times.
+// @Tags         weather
+// @Accept       json
+// @Produce      json
+// @Param        city  query     string  false  "City name, required unless lat and lon are given"
+// @Param        lat   query     number  false  "Latitude, -90 to 90"
+// @Param        lon   query     number  false  "Longitude, -180 to 180"
+// @Success      200   {object}  model.Alerts  "Active alerts returned"
+// @Failure      400   {object}  response.ErrorResponse   "Invalid request"
+// @Failure      404   {object}  response.ErrorResponse   "City not found"
+// @Failure      501   {object}  response.ErrorResponse   "Not supported by the weather provider"
+// @Failure      502   {object}  response.ErrorResponse   "Weather provider unavailable"
+// @Failure      503   {object}  response.ErrorResponse   "Weather provider quota exceeded or temporarily unavailable"
+// @Header       503   {integer} Retry-After  "Seconds until the weather provider is retried"
+// @Router       /alerts [get]
+func (h *WeatherHandler) GetAlerts(ctx *gin.Context) {
+	// Validate input
+	city, ok := locationQuery(ctx)
+	if !ok {
+		return
+	}
+
+	alerts, err := h.svc.FetchAlerts(ctx.Request.Context(), city)
+	if err != nil {
+		writeServiceError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, alerts)
+}
+
+// GetAstronomy godoc
+// @Summary      Get sunrise, sunset and moon data for a city
+// @Description  Returns sunrise, sunset, moonrise, moonset and moon phase for a date. When the weather provider is unavailable and the location is given as coordinates, sun times and moon phase are calculated locally.
+// @Tags         weather
+// @Accept       json
+// @Produce      json
+// @Param        city  query     string  false  "City name, required unless lat and lon are given"
+// @Param        lat   query     number  false  "Latitude, -90 to 90"
+// @Param        lon   query     number  false  "Longitude, -180 to 180"
+// @Param        date  query     string  false  "Date in YYYY-MM-DD format, defaults to today (UTC)"
+// @Param        lang  query     string  false  "Language of descriptions, overrides Accept-Language"  Enums(en,uk)
+// @Param        Accept-Language  header  string  false  "Preferred language of descriptions"
+// @Success      200   {object}  model.Astronomy  "Astronomy data returned"
+// @Failure      400   {object}  response.ErrorResponse   "Invalid request"
+// @Failure      404   {object}  response.ErrorResponse   "City not found"
+// @Failure      501   {object}  response.ErrorResponse   "Not supported by the weather provider"
+// @Failure      502   {object}  response.ErrorResponse   "Weather provider unavailable"
+// @Failure      503   {object}  response.ErrorResponse   "Weather provider quota exceeded or temporarily unavailable"
+// @Header       503   {integer} Retry-After  "Seconds until the weather provider is retried"
+// @Router       /astronomy [get]
+func (h *WeatherHandler) GetAstronomy(ctx *gin.Context) {
+	// Validate input
+	city, ok := locationQuery(ctx)
+	if !ok {
+		return
+	}
+	now := time.Now()
+	date := now.UTC().Truncate(24 * time.Hour)
+	if raw := ctx.Query("date"); raw != "" {
+		var err error
+		date, err = time.Parse(time.DateOnly, raw)
+		if err != nil || !validate.IsValidAstronomyDate(date, now) {
+			response.WriteErrorJSON(ctx, http.StatusBadRequest,
+				fmt.Errorf("invalid date parameter: %q", raw),
+				"Date must be in YYYY-MM-DD format and within a year of today")
+			return
 		}
-		response.WriteErrorJSON(ctx, code, err, msg)
+	}
+	lang, ok := languageQuery(ctx)
+	if !ok {
+		return
+	}
+
+	astro, err := h.svc.FetchAstronomy(ctx.Request.Context(), city, date)
+	if err != nil {
+		writeServiceError(ctx, err)
+		return
+	}
+	i18n.LocalizeAstronomy(astro, lang)
+	ctx.JSON(http.StatusOK, astro)
+}
+
+// SearchCities godoc
+// @Summary      Search cities
+// @Description  Returns locations matching a partial city name, best matches first, with a stable location ID. Used for autocomplete.
+// @Tags         locations
+// @Accept       json
+// @Produce      json
+// @Param        q    query     string  true  "Partial city name (2-100 characters)"
+// @Success      200  {array}   model.Location  "Matching locations, possibly empty"
+// @Failure      400  {object}  response.ErrorResponse   "Invalid request"
+// @Failure      502  {object}  response.ErrorResponse   "Weather provider unavailable"
+// @Failure      503  {object}  response.ErrorResponse   "Weather provider quota exceeded or temporarily unavailable"
+// @Header       503  {integer} Retry-After  "Seconds until the weather provider is retried"
+// @Router       /cities/search [get]
+func (h *WeatherHandler) SearchCities(ctx *gin.Context) {
+	query := ctx.Query("q")
+
+	// Validate input
+	if !validate.IsValidSearchQuery(query) {
+		response.WriteErrorJSON(ctx, http.StatusBadRequest,
+			fmt.Errorf("invalid q parameter: %q", query),
+			fmt.Sprintf("Query must be %d to %d characters long", validate.MinSearchQueryLength, validate.MaxSearchQueryLength))
+		return
+	}
+
+	locations, err := h.svc.SearchCities(ctx.Request.Context(), strings.TrimSpace(query))
+	if err != nil {
+		writeServiceError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, locations)
+}
+
+// locationQuery returns the location to look up: a "lat,lon" pair when the lat and lon
+// parameters are given, otherwise the city parameter. It writes a 400 response and
+// returns false if neither is valid.
+func locationQuery(ctx *gin.Context) (string, bool) {
+	rawLat, rawLon := ctx.Query("lat"), ctx.Query("lon")
+	if rawLat == "" && rawLon == "" {
+		city := ctx.Query("city")
+		if !validate.IsValidCity(city) {
+			response.WriteErrorJSON(ctx, http.StatusBadRequest,
+				fmt.Errorf("invalid city parameter"),
+				"City parameter or lat/lon parameters are required")
+			return "", false
+		}
+		return city, true
+	}
+
+	lat, latErr := strconv.ParseFloat(rawLat, 64)
+	lon, lonErr := strconv.ParseFloat(rawLon, 64)
+	if latErr != nil || lonErr != nil || !validate.IsValidLatitude(lat) || !validate.IsValidLongitude(lon) {
+		response.WriteErrorJSON(ctx, http.StatusBadRequest,
+			fmt.Errorf("invalid coordinates: lat=%q lon=%q", rawLat, rawLon),
+			"Lat must be between -90 and 90 and lon between -180 and 180")
+		return "", false
+	}
+	return model.Coordinates{Lat: lat, Lon: lon}.String(), true
+}
+
+// unitsQuery returns the unit system selected by the units parameter, metric by default.
+// It writes a 400 response and returns false if the system is unknown.
+func unitsQuery(ctx *gin.Context) (units.System, bool) {
+	system, ok := units.Parse(ctx.Query("units"))
+	if !ok {
+		response.WriteErrorJSON(ctx, http.StatusBadRequest,
+			fmt.Errorf("invalid units parameter: %q", ctx.Query("units")),
+			"Units must be one of: metric, imperial, scientific")
+		return "", false
+	}
+	return system, true
+}
+
+// languageQuery returns the language selected by the lang parameter, or else the one preferred
+// by the Accept-Language header, and announces it in the Content-Language header.
+// It writes a 400 response and returns false if the lang parameter is unsupported.
+func languageQuery(ctx *gin.Context) (i18n.Language, bool) {
+	lang := i18n.Negotiate(ctx.GetHeader("Accept-Language"))
+	if raw := ctx.Query("lang"); raw != "" {
+		var ok bool
+		if lang, ok = i18n.Parse(raw); !ok {
+			response.WriteErrorJSON(ctx, http.StatusBadRequest,
+				fmt.Errorf("invalid lang parameter: %q", raw),
+				"Lang must be one of: en, uk")
+			return "", false
+		}
+	}
+	ctx.Header("Content-Language", string(lang))
+	return lang, true
+}
+
+// batchLocation returns the location to look up for a batch item, validated like locationQuery.
+func batchLocation(loc model.BatchLocation) (string, error) {
+	if loc.Lat == nil && loc.Lon == nil {
+		if !validate.IsValidCity(loc.City) {
+			return loc.City, errors.New("City or lat/lon are required")
+		}
+		return loc.City, nil
+	}
+	if loc.Lat == nil || loc.Lon == nil || !validate.IsValidLatitude(*loc.Lat) || !validate.IsValidLongitude(*loc.Lon) {
+		return loc.City, errors.New("Lat must be between -90 and 90 and lon between -180 and 180")
+	}
+	return model.Coordinates{Lat: *loc.Lat, Lon: *loc.Lon}.String(), nil
+}
+
+// parseTimeQuery parses an optional RFC3339 query parameter, returning def when it is absent.
+func parseTimeQuery(ctx *gin.Context, key string, def time.Time) (time.Time, error) {
+	raw := ctx.Query(key)
+	if raw == "" {
+		return def, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s parameter: %w", key, err)
+	}
+	return t.UTC(), nil
+}
+
+// statusClientClosedRequest is the non-standard status logged when the client goes away
+// before the response is ready.
+const statusClientClosedRequest = 499
+
+// writeServiceError writes a weather service error as its HTTP status and user-facing message.
+// Errors caused by an open circuit breaker also tell the client when to retry.
+func writeServiceError(ctx *gin.Context, err error) {
+	var openErr *resilience.OpenError
+	if errors.As(err, &openErr) {
+		ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(openErr.RetryAfter.Seconds()))))
+	}
+	code, msg := serviceErrorResponse(err)
+	response.WriteErrorJSON(ctx, code, err, msg)
+}
+
+// serviceErrorResponse maps a weather service error to an HTTP status and user-facing message.
+func serviceErrorResponse(err error) (int, string) {
+	switch {
+	case errors.Is(err, weather_service.ErrInvalidInput):
+		return http.StatusBadRequest, "Invalid request"
+	case errors.Is(err, weather_service.ErrCityNotFound):
+		return http.StatusNotFound, "City not found"
+	case errors.Is(err, weather_service.ErrNoData):
+		return http.StatusNotFound, "No weather data found for this date"
+	case errors.Is(err, weather_service.ErrNotSupported):
+		return http.StatusNotImplemented, "Not supported by the weather provider"
+	case errors.Is(err, weather_service.ErrCircuitOpen):
+		return http.StatusServiceUnavailable, "Weather provider temporarily unavailable, try again later"
+	case errors.Is(err, weather_service.ErrQuotaExceeded):
+		return http.StatusServiceUnavailable, "Weather provider quota exceeded, try again later"
+	case errors.Is(err, weather_service.ErrUpstreamUnavailable):
+		return http.StatusBadGateway, "Weather provider unavailable"
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout, "Request timed out"
+	case errors.Is(err, context.Canceled):
+		return statusClientClosedRequest, "Request cancelled"
+	}
+	return http.StatusInternalServerError, "Internal server error"
+}
+
+// setCacheHeaders exposes how the response was served by the weather cache.
+func setCacheHeaders(ctx *gin.Context, info *model.CacheInfo) {
+	if info == nil {
 		return
 	}
-	ctx.JSON(200, fetchedWeather)
+	ctx.Header("X-Cache", info.Status)
+	ctx.Header("Age", strconv.Itoa(int(info.Age.Seconds())))
 }
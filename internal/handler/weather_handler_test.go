@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"Weather-API-Application/internal/config"
+	"Weather-API-Application/internal/model"
+	"Weather-API-Application/internal/resilience"
+	"Weather-API-Application/internal/services/weather_service"
+	"Weather-API-Application/internal/utils/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeWeatherService answers batches with the weather of each city, or its error in errs.
+type fakeWeatherService struct {
+	weather_service.WeatherService
+	errs    map[string]error
+	batches [][]string
+}
+
+func (f *fakeWeatherService) FetchWeatherForCities(_ context.Context, cities []string) []weather_service.WeatherResult {
+	f.batches = append(f.batches, cities)
+	results := make([]weather_service.WeatherResult, len(cities))
+	for i, city := range cities {
+		if err := f.errs[city]; err != nil {
+			results[i].Err = err
+			continue
+		}
+		results[i].Weather = &model.Weather{Temperature: 20, Description: "Clear"}
+	}
+	return results
+}
+
+func newTestRouter(svc weather_service.WeatherService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewWeatherHandler(&config.Config{WeatherBatchMaxItems: 3}, svc).RegisterRoutes(router)
+	return router
+}
+
+func TestGetWeatherBatch(t *testing.T) {
+	svc := &fakeWeatherService{errs: map[string]error{
+		"Atlantis": fmt.Errorf("%w: unknown", weather_service.ErrCityNotFound),
+	}}
+	router := newTestRouter(svc)
+
+	body := `{"locations":[{"city":"Kyiv"},{"lat":91,"lon":0},{"city":"Atlantis"}]}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/weather/batch?units=imperial", strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp model.BatchWeatherResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Results, 3)
+
+	require.Equal(t, "Kyiv", resp.Results[0].Location)
+	require.Equal(t, http.StatusOK, resp.Results[0].Status)
+	require.Equal(t, 68.0, resp.Results[0].Weather.Temperature)
+
+	require.Equal(t, http.StatusBadRequest, resp.Results[1].Status)
+	require.NotEmpty(t, resp.Results[1].Error)
+	require.Nil(t, resp.Results[1].Weather)
+
+	require.Equal(t, "Atlantis", resp.Results[2].Location)
+	require.Equal(t, http.StatusNotFound, resp.Results[2].Status)
+	require.Equal(t, "City not found", resp.Results[2].Error)
+
+	// Invalid items are not looked up
+	require.Equal(t, [][]string{{"Kyiv", "Atlantis"}}, svc.batches)
+}
+
+func TestGetWeatherBatchInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "too many locations", body: `{"locations":[{"city":"Kyiv"},{"city":"Lviv"},{"city":"Odesa"},{"city":"Dnipro"}]}`},
+		{name: "no locations", body: `{"locations":[]}`},
+		{name: "malformed body", body: `{"locations":`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &fakeWeatherService{}
+			router := newTestRouter(svc)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/weather/batch", strings.NewReader(tt.body)))
+			require.Equal(t, http.StatusBadRequest, w.Code)
+			require.Empty(t, svc.batches)
+		})
+	}
+}
+
+func TestWriteServiceError(t *testing.T) {
+	openErr := &resilience.OpenError{Name: "weatherapi", RetryAfter: 1500 * time.Millisecond}
+
+	tests := []struct {
+		name           string
+		err            error
+		wantStatus     int
+		wantRetryAfter string
+	}{
+		{name: "invalid input", err: weather_service.ErrInvalidInput, wantStatus: http.StatusBadRequest},
+		{name: "city not found", err: weather_service.ErrCityNotFound, wantStatus: http.StatusNotFound},
+		{name: "no data", err: weather_service.ErrNoData, wantStatus: http.StatusNotFound},
+		{name: "not supported", err: weather_service.ErrNotSupported, wantStatus: http.StatusNotImplemented},
+		{name: "quota exceeded", err: weather_service.ErrQuotaExceeded, wantStatus: http.StatusServiceUnavailable},
+		{
+			name:           "circuit open",
+			err:            fmt.Errorf("%w: %w", weather_service.ErrCircuitOpen, openErr),
+			wantStatus:     http.StatusServiceUnavailable,
+			wantRetryAfter: "2",
+		},
+		{name: "upstream unavailable", err: weather_service.ErrUpstreamUnavailable, wantStatus: http.StatusBadGateway},
+		{name: "timed out", err: fmt.Errorf("%w: slow", context.DeadlineExceeded), wantStatus: http.StatusGatewayTimeout},
+		{name: "cancelled", err: context.Canceled, wantStatus: statusClientClosedRequest},
+		{name: "unexpected error", err: errors.New("boom"), wantStatus: http.StatusInternalServerError},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			writeServiceError(ctx, tt.err)
+			require.Equal(t, tt.wantStatus, w.Code)
+			require.Equal(t, tt.wantRetryAfter, w.Header().Get("Retry-After"))
+
+			var resp response.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			require.NotEmpty(t, resp.Error)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package i18n
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Language is a supported language, identified by its ISO 639-1 code.
+type Language string
+
+const (
+	English   Language = "en"
+	Ukrainian Language = "uk"
+)
+
+// Default is used when no supported language is requested.
+const Default = English
+
+// Supported lists the supported languages.
+var Supported = []Language{English, Ukrainian}
+
+// aliases maps other common codes to supported languages.
+var aliases = map[string]Language{
+	"ua": Ukrainian,
+}
+
+// Parse returns the language with the given code, e.g. "uk" or "uk-UA".
+// An empty code selects Default.
+func Parse(code string) (Language, bool) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if code == "" {
+		return Default, true
+	}
+	base, _, _ := strings.Cut(code, "-")
+	if lang, ok := aliases[base]; ok {
+		return lang, true
+	}
+	lang := Language(base)
+	return lang, slices.Contains(Supported, lang)
+}
+
+// Negotiate picks the supported language preferred by an Accept-Language header,
+// honouring quality values. It returns Default if none of the languages is supported.
+func Negotiate(acceptLanguage string) Language {
+	type candidate struct {
+		lang    Language
+		quality float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		lang, ok := Parse(tag)
+		if !ok || strings.TrimSpace(tag) == "" {
+			continue
+		}
+
+		quality := 1.0
+		if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			v, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = v
+		}
+		if quality > 0 {
+			candidates = append(candidates, candidate{lang: lang, quality: quality})
+		}
+	}
+
+	// Stable sort keeps the header order for equal quality values
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		switch {
+		case a.quality > b.quality:
+			return -1
+		case a.quality < b.quality:
+			return 1
+		}
+		return 0
+	})
+	if len(candidates) == 0 {
+		return Default
+	}
+	return candidates[0].lang
+}
+
+// Message formats the catalog message for key, falling back to English
+// when the message is not translated.
+func (l Language) Message(key Key, args ...any) string {
+	format, ok := catalog[l][key]
+	if !ok {
+		format = catalog[English][key]
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Text translates an English phrase reported by the weather providers, such as a condition
+// or moon phase. Unknown phrases are returned unchanged.
+func (l Language) Text(english string) string {
+	if l == English {
+		return english
+	}
+	if translated, ok := texts[l][strings.ToLower(strings.TrimSpace(english))]; ok {
+		return translated
+	}
+	return english
+}
@@ -0,0 +1,66 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		code   string
+		want   Language
+		wantOk bool
+	}{
+		{"default", "", English, true},
+		{"english", "en", English, true},
+		{"region", "uk-UA", Ukrainian, true},
+		{"alias", "UA", Ukrainian, true},
+		{"unsupported", "de", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Parse(tt.code)
+			require.Equal(t, tt.wantOk, ok)
+			if ok {
+				require.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   Language
+	}{
+		{"empty", "", English},
+		{"single", "uk", Ukrainian},
+		{"browser default", "uk-UA,uk;q=0.9,en-US;q=0.8,en;q=0.7", Ukrainian},
+		{"quality order", "en;q=0.5,uk;q=0.8", Ukrainian},
+		{"unsupported first", "de-DE,de;q=0.9,uk;q=0.5", Ukrainian},
+		{"nothing supported", "de,fr", English},
+		{"zero quality", "uk;q=0,en", English},
+		{"wildcard", "*", English},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Negotiate(tt.header))
+		})
+	}
+}
+
+func TestText(t *testing.T) {
+	require.Equal(t, "Мінлива хмарність", Ukrainian.Text("Partly cloudy"))
+	require.Equal(t, "Мінлива хмарність", Ukrainian.Text(" partly cloudy "))
+	require.Equal(t, "Partly cloudy", English.Text("Partly cloudy"))
+	require.Equal(t, "Volcanic ash", Ukrainian.Text("Volcanic ash"))
+}
+
+func TestMessage(t *testing.T) {
+	require.Equal(t, "Kyiv forecast", English.Message(UpdateSubject, "Kyiv"))
+	require.Equal(t, "Прогноз погоди: Київ", Ukrainian.Message(UpdateSubject, "Київ"))
+	require.Equal(t, "Confirm your subscription", Language("de").Message(ConfirmSubject))
+}
@@ -0,0 +1,44 @@
+package i18n
+
+import "Weather-API-Application/internal/model"
+
+// LocalizeWeather translates the weather description and air quality categories in place.
+func LocalizeWeather(w *model.Weather, l Language) {
+	w.Description = l.Text(w.Description)
+	if w.AirQuality != nil {
+		LocalizeAirQuality(w.AirQuality, l)
+	}
+}
+
+// LocalizeAirQuality translates the air quality categories in place.
+func LocalizeAirQuality(aq *model.AirQuality, l Language) {
+	aq.USEPACategory = l.Text(aq.USEPACategory)
+	aq.GBDEFRACategory = l.Text(aq.GBDEFRACategory)
+}
+
+// LocalizeForecast translates the daily conditions in place.
+func LocalizeForecast(f *model.Forecast, l Language) {
+	for i := range f.Days {
+		f.Days[i].Condition = l.Text(f.Days[i].Condition)
+	}
+}
+
+// LocalizeHourlyForecast translates the hourly conditions in place.
+func LocalizeHourlyForecast(f *model.HourlyForecast, l Language) {
+	for i := range f.Hours {
+		f.Hours[i].Condition = l.Text(f.Hours[i].Condition)
+	}
+}
+
+// LocalizeHistory translates the daily and hourly conditions in place.
+func LocalizeHistory(h *model.History, l Language) {
+	h.Condition = l.Text(h.Condition)
+	for i := range h.Hours {
+		h.Hours[i].Condition = l.Text(h.Hours[i].Condition)
+	}
+}
+
+// LocalizeAstronomy translates the moon phase in place.
+func LocalizeAstronomy(a *model.Astronomy, l Language) {
+	a.MoonPhase = l.Text(a.MoonPhase)
+}
@@ -0,0 +1,36 @@
+package i18n
+
+// Key identifies a message in the catalog.
+type Key string
+
+const (
+	ConfirmSubject   Key = "confirm.subject"
+	ConfirmBody      Key = "confirm.body"
+	UpdateSubject    Key = "update.subject"
+	UpdateBody       Key = "update.body"
+	UpdateAirQuality Key = "update.air_quality"
+	AlertSubject     Key = "alert.subject"
+	AlertBody        Key = "alert.body"
+)
+
+// catalog holds the email copy. Messages are fmt format strings.
+var catalog = map[Language]map[Key]string{
+	English: {
+		ConfirmSubject:   "Confirm your subscription",
+		ConfirmBody:      `<p>Click <a href="%s/api/subscription/confirm/%s">here</a> to confirm your subscription.</p>`,
+		UpdateSubject:    "%s forecast",
+		UpdateBody:       `Weather for %s:<br>- temperature: %.1f%s<br>- humidity: %.0f%%<br>- description: %s`,
+		UpdateAirQuality: `<br>- air quality: %s (US EPA index %d, UK DAQI %d)<br>- PM2.5: %.1f µg/m³, PM10: %.1f µg/m³, O3: %.1f µg/m³, NO2: %.1f µg/m³`,
+		AlertSubject:     "Weather alert for %s: %s",
+		AlertBody:        `<b>%s</b><br>- event: %s<br>- severity: %s<br>- areas: %s<br>- effective: %s<br>- expires: %s<br><br>%s`,
+	},
+	Ukrainian: {
+		ConfirmSubject:   "Підтвердіть підписку",
+		ConfirmBody:      `<p>Натисніть <a href="%s/api/subscription/confirm/%s">тут</a>, щоб підтвердити підписку.</p>`,
+		UpdateSubject:    "Прогноз погоди: %s",
+		UpdateBody:       `Погода для %s:<br>- температура: %.1f%s<br>- вологість: %.0f%%<br>- опис: %s`,
+		UpdateAirQuality: `<br>- якість повітря: %s (індекс US EPA %d, UK DAQI %d)<br>- PM2.5: %.1f мкг/м³, PM10: %.1f мкг/м³, O3: %.1f мкг/м³, NO2: %.1f мкг/м³`,
+		AlertSubject:     "Попередження про погоду для %s: %s",
+		AlertBody:        `<b>%s</b><br>- подія: %s<br>- рівень небезпеки: %s<br>- території: %s<br>- початок: %s<br>- завершення: %s<br><br>%s`,
+	},
+}
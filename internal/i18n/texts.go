@@ -0,0 +1,124 @@
+package i18n
+
+// texts translates the English phrases reported by the weather providers (WeatherAPI.com
+// conditions, Open-Meteo WMO descriptions, OpenWeatherMap descriptions), moon phases and
+// air quality categories. Keys are lower-case.
+var texts = map[Language]map[string]string{
+	Ukrainian: {
+		// WeatherAPI.com conditions
+		"sunny":                                       "Сонячно",
+		"clear":                                       "Ясно",
+		"partly cloudy":                               "Мінлива хмарність",
+		"cloudy":                                      "Хмарно",
+		"overcast":                                    "Похмуро",
+		"mist":                                        "Серпанок",
+		"patchy rain possible":                        "Місцями можливий дощ",
+		"patchy rain nearby":                          "Місцями можливий дощ",
+		"patchy snow possible":                        "Місцями можливий сніг",
+		"patchy snow nearby":                          "Місцями можливий сніг",
+		"patchy sleet possible":                       "Місцями можливий мокрий сніг",
+		"patchy sleet nearby":                         "Місцями можливий мокрий сніг",
+		"patchy freezing drizzle possible":            "Місцями можлива крижана мряка",
+		"patchy freezing drizzle nearby":              "Місцями можлива крижана мряка",
+		"thundery outbreaks possible":                 "Можливі грози",
+		"thundery outbreaks in nearby":                "Можливі грози",
+		"blowing snow":                                "Поземок",
+		"blizzard":                                    "Хуртовина",
+		"fog":                                         "Туман",
+		"freezing fog":                                "Крижаний туман",
+		"patchy light drizzle":                        "Місцями легка мряка",
+		"light drizzle":                               "Легка мряка",
+		"freezing drizzle":                            "Крижана мряка",
+		"heavy freezing drizzle":                      "Сильна крижана мряка",
+		"patchy light rain":                           "Місцями невеликий дощ",
+		"light rain":                                  "Невеликий дощ",
+		"moderate rain at times":                      "Часом помірний дощ",
+		"moderate rain":                               "Помірний дощ",
+		"heavy rain at times":                         "Часом сильний дощ",
+		"heavy rain":                                  "Сильний дощ",
+		"light freezing rain":                         "Невеликий крижаний дощ",
+		"moderate or heavy freezing rain":             "Помірний або сильний крижаний дощ",
+		"light sleet":                                 "Невеликий мокрий сніг",
+		"moderate or heavy sleet":                     "Помірний або сильний мокрий сніг",
+		"patchy light snow":                           "Місцями невеликий сніг",
+		"light snow":                                  "Невеликий сніг",
+		"patchy moderate snow":                        "Місцями помірний сніг",
+		"moderate snow":                               "Помірний сніг",
+		"patchy heavy snow":                           "Місцями сильний сніг",
+		"heavy snow":                                  "Сильний сніг",
+		"ice pellets":                                 "Крижана крупа",
+		"light rain shower":                           "Невелика злива",
+		"moderate or heavy rain shower":               "Помірна або сильна злива",
+		"torrential rain shower":                      "Проливна злива",
+		"light sleet showers":                         "Невеликий мокрий сніг з дощем",
+		"moderate or heavy sleet showers":             "Помірний або сильний мокрий сніг з дощем",
+		"light snow showers":                          "Невеликий снігопад",
+		"moderate or heavy snow showers":              "Помірний або сильний снігопад",
+		"light showers of ice pellets":                "Невеликі опади крижаної крупи",
+		"moderate or heavy showers of ice pellets":    "Помірні або сильні опади крижаної крупи",
+		"patchy light rain with thunder":              "Місцями невеликий дощ з грозою",
+		"patchy light rain in area with thunder":      "Місцями невеликий дощ з грозою",
+		"moderate or heavy rain with thunder":         "Помірний або сильний дощ з грозою",
+		"moderate or heavy rain in area with thunder": "Помірний або сильний дощ з грозою",
+		"patchy light snow with thunder":              "Місцями невеликий сніг з грозою",
+		"patchy light snow in area with thunder":      "Місцями невеликий сніг з грозою",
+		"moderate or heavy snow with thunder":         "Помірний або сильний сніг з грозою",
+		"moderate or heavy snow in area with thunder": "Помірний або сильний сніг з грозою",
+
+		// Open-Meteo WMO descriptions
+		"clear sky":                     "Ясне небо",
+		"mainly clear":                  "Переважно ясно",
+		"depositing rime fog":           "Туман з памороззю",
+		"moderate drizzle":              "Помірна мряка",
+		"dense drizzle":                 "Густа мряка",
+		"light freezing drizzle":        "Легка крижана мряка",
+		"dense freezing drizzle":        "Густа крижана мряка",
+		"slight rain":                   "Невеликий дощ",
+		"heavy freezing rain":           "Сильний крижаний дощ",
+		"slight snow fall":              "Невеликий снігопад",
+		"moderate snow fall":            "Помірний снігопад",
+		"heavy snow fall":               "Сильний снігопад",
+		"snow grains":                   "Снігова крупа",
+		"slight rain showers":           "Невелика злива",
+		"moderate rain showers":         "Помірна злива",
+		"violent rain showers":          "Дуже сильна злива",
+		"slight snow showers":           "Невеликий снігопад",
+		"heavy snow showers":            "Сильний снігопад",
+		"thunderstorm":                  "Гроза",
+		"thunderstorm with slight hail": "Гроза з невеликим градом",
+		"thunderstorm with heavy hail":  "Гроза з сильним градом",
+
+		// OpenWeatherMap descriptions
+		"few clouds":       "Невелика хмарність",
+		"scattered clouds": "Розсіяні хмари",
+		"broken clouds":    "Хмарно з проясненнями",
+		"overcast clouds":  "Суцільна хмарність",
+		"shower rain":      "Злива",
+		"rain":             "Дощ",
+		"snow":             "Сніг",
+		"haze":             "Імла",
+		"smoke":            "Дим",
+		"dust":             "Пил",
+
+		// Moon phases
+		"new moon":        "Молодик",
+		"waxing crescent": "Молодий місяць",
+		"first quarter":   "Перша чверть",
+		"waxing gibbous":  "Прибуваючий місяць",
+		"full moon":       "Повня",
+		"waning gibbous":  "Спадаючий місяць",
+		"last quarter":    "Остання чверть",
+		"waning crescent": "Старий місяць",
+
+		// Air quality categories
+		"good":                           "Добра",
+		"moderate":                       "Помірна",
+		"unhealthy for sensitive groups": "Шкідлива для чутливих груп",
+		"unhealthy":                      "Шкідлива",
+		"very unhealthy":                 "Дуже шкідлива",
+		"hazardous":                      "Небезпечна",
+		"low":                            "Низький рівень",
+		"high":                           "Високий рівень",
+		"very high":                      "Дуже високий рівень",
+	},
+}
@@ -0,0 +1,101 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"Weather-API-Application/internal/logger"
+)
+
+// Advisory lock keys of the leader elections.
+const (
+	SchedulerLockKey        int64 = 0x5745415448455201
+	LocationBackfillLockKey int64 = 0x5745415448455202
+)
+
+// Leader elects one instance among those sharing the database by holding a session-level
+// Postgres advisory lock on a dedicated connection. Postgres releases the lock when the
+// connection closes, so a crashed leader is replaced within one check interval.
+type Leader struct {
+	db       *sql.DB
+	key      int64
+	interval time.Duration
+}
+
+// NewLeader creates a leader election on the advisory lock key. Instances that are not
+// the leader try to take the lock, and the leader checks its connection, every interval.
+func NewLeader(db *sql.DB, key int64, interval time.Duration) *Leader {
+	return &Leader{db: db, key: key, interval: interval}
+}
+
+// Run runs fn whenever this instance becomes the leader, until ctx is cancelled. The context
+// passed to fn is cancelled when leadership is lost. Another instance may take over before the
+// loss is noticed, so fn must tolerate briefly running alongside a new leader.
+func (l *Leader) Run(ctx context.Context, fn func(ctx context.Context)) {
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := l.lead(ctx, ticker, fn); err != nil && ctx.Err() == nil {
+			logger.Error(ctx, fmt.Errorf("leader election failed: %w", err), slog.Int64("lock_key", l.key))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// lead takes the lock if it is free and runs fn while holding it.
+func (l *Leader) lead(ctx context.Context, ticker *time.Ticker, fn func(ctx context.Context)) error {
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, l.key).Scan(&acquired); err != nil {
+		return err
+	}
+	if !acquired {
+		return nil
+	}
+	defer func() {
+		// The connection goes back to the pool, so the session lock must not outlive leadership
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, l.key); err != nil {
+			logger.Error(ctx, fmt.Errorf("failed to release leader lock: %w", err), slog.Int64("lock_key", l.key))
+		}
+	}()
+
+	logger.Info(ctx, "Became leader", slog.Int64("lock_key", l.key))
+	leadCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn(leadCtx)
+	}()
+
+	for {
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			cancel()
+			<-done
+			return nil
+		case <-ticker.C:
+			if err := conn.PingContext(ctx); err != nil {
+				cancel()
+				<-done
+				return fmt.Errorf("lost leader connection: %w", err)
+			}
+		}
+	}
+}
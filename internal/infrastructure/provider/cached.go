@@ -0,0 +1,110 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"Weather-API-Application/internal/cache"
+	"Weather-API-Application/internal/model"
+	"Weather-API-Application/internal/provider"
+	"Weather-API-Application/internal/quota"
+	"Weather-API-Application/internal/resilience"
+)
+
+// CachedProvider serves current weather, air quality, alerts and location searches from an in-memory cache
+// in front of another provider. Other calls are passed through. When the provider is over budget or
+// its circuit breaker is open, expired entries up to maxStaleAge old are served rather than failing.
+// Reloads of cached entries are made with quota.WithFallback, so the meter may refuse them early.
+type CachedProvider struct {
+	provider.WeatherProvider
+	current    *cache.Cache[*model.Weather]
+	airQuality *cache.Cache[*model.AirQuality]
+	alerts     *cache.Cache[*model.Alerts]
+	locations  *cache.Cache[[]model.Location]
+}
+
+func NewCachedProvider(next provider.WeatherProvider, ttl, staleTTL, maxStaleAge time.Duration, maxEntries int) provider.WeatherProvider {
+	return &CachedProvider{
+		WeatherProvider: next,
+		current:         cache.New[*model.Weather](ttl, staleTTL, maxEntries).ServeExpiredOn(maxStaleAge, isUnavailable),
+		airQuality:      cache.New[*model.AirQuality](ttl, staleTTL, maxEntries).ServeExpiredOn(maxStaleAge, isUnavailable),
+		alerts:          cache.New[*model.Alerts](ttl, staleTTL, maxEntries).ServeExpiredOn(maxStaleAge, isUnavailable),
+		locations:       cache.New[[]model.Location](ttl, staleTTL, maxEntries).ServeExpiredOn(maxStaleAge, isUnavailable),
+	}
+}
+
+// CurrentWeather returns a copy of the cached weather annotated with cache status and age.
+func (p *CachedProvider) CurrentWeather(ctx context.Context, location string) (*model.Weather, error) {
+	weather, info, err := p.current.Get(ctx, cacheKey(location), func(ctx context.Context) (*model.Weather, error) {
+		return p.WeatherProvider.CurrentWeather(withFallback(ctx), location)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := *weather
+	out.Cache = &model.CacheInfo{Status: string(info.Status), Age: info.Age}
+	return &out, nil
+}
+
+// AirQuality returns the cached air quality for the location.
+func (p *CachedProvider) AirQuality(ctx context.Context, location string) (*model.AirQuality, error) {
+	airQuality, _, err := p.airQuality.Get(ctx, cacheKey(location), func(ctx context.Context) (*model.AirQuality, error) {
+		return p.WeatherProvider.AirQuality(withFallback(ctx), location)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := *airQuality
+	return &out, nil
+}
+
+// Alerts returns the cached alerts for the location.
+func (p *CachedProvider) Alerts(ctx context.Context, location string) (*model.Alerts, error) {
+	alerts, _, err := p.alerts.Get(ctx, cacheKey(location), func(ctx context.Context) (*model.Alerts, error) {
+		return p.WeatherProvider.Alerts(withFallback(ctx), location)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := *alerts
+	out.Alerts = slices.Clone(alerts.Alerts)
+	return &out, nil
+}
+
+// SearchLocations returns the cached search results for the query.
+func (p *CachedProvider) SearchLocations(ctx context.Context, query string) ([]model.Location, error) {
+	locations, _, err := p.locations.Get(ctx, cacheKey(query), func(ctx context.Context) ([]model.Location, error) {
+		return p.WeatherProvider.SearchLocations(withFallback(ctx), query)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return slices.Clone(locations), nil
+}
+
+// isUnavailable reports whether the provider refused the call without answering it.
+func isUnavailable(err error) bool {
+	return errors.Is(err, provider.ErrQuotaExceeded) || errors.Is(err, resilience.ErrOpen)
+}
+
+// withFallback marks the upstream calls of a load as having a fallback when the cache holds
+// an older value, which is served if the meter refuses them.
+func withFallback(ctx context.Context) context.Context {
+	if cache.HasFallback(ctx) {
+		return quota.WithFallback(ctx)
+	}
+	return ctx
+}
+
+func cacheKey(parts ...string) string {
+	for i, part := range parts {
+		parts[i] = strings.ToLower(strings.TrimSpace(part))
+	}
+	return strings.Join(parts, "|")
+}
@@ -0,0 +1,279 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"Weather-API-Application/internal/logger"
+	"Weather-API-Application/internal/model"
+	"Weather-API-Application/internal/provider"
+	"Weather-API-Application/internal/resilience"
+)
+
+// FailoverProvider queries providers in priority order and moves on to the next one
+// when a provider times out, is unreachable, responds with a 5xx error or has an open
+// circuit breaker.
+type FailoverProvider struct {
+	providers []provider.WeatherProvider
+	timeout   time.Duration
+}
+
+func NewFailoverProvider(timeout time.Duration, providers ...provider.WeatherProvider) provider.WeatherProvider {
+	return &FailoverProvider{
+		providers: providers,
+		timeout:   timeout,
+	}
+}
+
+func (p *FailoverProvider) Name() string {
+	return "failover(" + joinNames(p.providers) + ")"
+}
+
+func (p *FailoverProvider) CurrentWeather(ctx context.Context, location string) (*model.Weather, error) {
+	return failover(ctx, p.providers, p.timeout, func(ctx context.Context, wp provider.WeatherProvider) (*model.Weather, error) {
+		return wp.CurrentWeather(ctx, location)
+	})
+}
+
+func (p *FailoverProvider) Forecast(ctx context.Context, location string, days int) (*model.Forecast, error) {
+	return failover(ctx, p.providers, p.timeout, func(ctx context.Context, wp provider.WeatherProvider) (*model.Forecast, error) {
+		return wp.Forecast(ctx, location, days)
+	})
+}
+
+func (p *FailoverProvider) HourlyForecast(ctx context.Context, location string, from, to time.Time) (*model.HourlyForecast, error) {
+	return failover(ctx, p.providers, p.timeout, func(ctx context.Context, wp provider.WeatherProvider) (*model.HourlyForecast, error) {
+		return wp.HourlyForecast(ctx, location, from, to)
+	})
+}
+
+func (p *FailoverProvider) History(ctx context.Context, location string, date time.Time) (*model.History, error) {
+	return failover(ctx, p.providers, p.timeout, func(ctx context.Context, wp provider.WeatherProvider) (*model.History, error) {
+		return wp.History(ctx, location, date)
+	})
+}
+
+func (p *FailoverProvider) AirQuality(ctx context.Context, location string) (*model.AirQuality, error) {
+	return failover(ctx, p.providers, p.timeout, func(ctx context.Context, wp provider.WeatherProvider) (*model.AirQuality, error) {
+		return wp.AirQuality(ctx, location)
+	})
+}
+
+func (p *FailoverProvider) Alerts(ctx context.Context, location string) (*model.Alerts, error) {
+	return failover(ctx, p.providers, p.timeout, func(ctx context.Context, wp provider.WeatherProvider) (*model.Alerts, error) {
+		return wp.Alerts(ctx, location)
+	})
+}
+
+func (p *FailoverProvider) Astronomy(ctx context.Context, location string, date time.Time) (*model.Astronomy, error) {
+	return failover(ctx, p.providers, p.timeout, func(ctx context.Context, wp provider.WeatherProvider) (*model.Astronomy, error) {
+		return wp.Astronomy(ctx, location, date)
+	})
+}
+
+func (p *FailoverProvider) SearchLocations(ctx context.Context, query string) ([]model.Location, error) {
+	return failover(ctx, p.providers, p.timeout, func(ctx context.Context, wp provider.WeatherProvider) ([]model.Location, error) {
+		return wp.SearchLocations(ctx, query)
+	})
+}
+
+// ResolveLocation does not fail over: location IDs are prefixed with the provider name, so only
+// the primary provider is asked to keep the same place under one canonical ID.
+func (p *FailoverProvider) ResolveLocation(ctx context.Context, query string) (*model.Location, error) {
+	return resolvePrimary(ctx, p.providers, p.timeout, query)
+}
+
+// failover calls fn for each provider in order until one succeeds or fails with a non-transient error.
+func failover[T any](ctx context.Context, providers []provider.WeatherProvider, timeout time.Duration, fn func(context.Context, provider.WeatherProvider) (T, error)) (T, error) {
+	var (
+		zero    T
+		lastErr error
+	)
+	for _, wp := range providers {
+		res, err := callWithTimeout(ctx, timeout, wp, fn)
+		if err == nil {
+			return res, nil
+		}
+		if ctx.Err() != nil {
+			return zero, ctx.Err()
+		}
+		if !provider.IsTransient(err) && !errors.Is(err, provider.ErrNotSupported) && !errors.Is(err, resilience.ErrOpen) {
+			return zero, err
+		}
+		logger.Info(ctx, "Weather provider failed, failing over",
+			slog.String("provider", wp.Name()),
+			slog.String("error", err.Error()))
+		lastErr = fmt.Errorf("%s: %w", wp.Name(), err)
+	}
+	return zero, fmt.Errorf("all weather providers failed: %w", lastErr)
+}
+
+// ConsensusProvider queries all providers concurrently and combines their answers:
+// temperature and humidity are the median of all successful responses.
+type ConsensusProvider struct {
+	providers []provider.WeatherProvider
+	timeout   time.Duration
+}
+
+func NewConsensusProvider(timeout time.Duration, providers ...provider.WeatherProvider) provider.WeatherProvider {
+	return &ConsensusProvider{
+		providers: providers,
+		timeout:   timeout,
+	}
+}
+
+func (p *ConsensusProvider) Name() string {
+	return "consensus(" + joinNames(p.providers) + ")"
+}
+
+// CurrentWeather returns the median temperature and humidity of all providers that answered.
+// The other fields are taken from the highest-priority provider that answered, and
+// Sources lists every provider that contributed, in priority order.
+func (p *ConsensusProvider) CurrentWeather(ctx context.Context, location string) (*model.Weather, error) {
+	results := make([]*model.Weather, len(p.providers))
+	errs := make([]error, len(p.providers))
+
+	var wg sync.WaitGroup
+	for i, wp := range p.providers {
+		wg.Add(1)
+		go func(i int, wp provider.WeatherProvider) {
+			defer wg.Done()
+			results[i], errs[i] = callWithTimeout(ctx, p.timeout, wp, func(ctx context.Context, wp provider.WeatherProvider) (*model.Weather, error) {
+				return wp.CurrentWeather(ctx, location)
+			})
+		}(i, wp)
+	}
+	wg.Wait()
+
+	var (
+		temperatures []float64
+		humidities   []float64
+		combined     *model.Weather
+	)
+	for i, w := range results {
+		if errs[i] != nil {
+			logger.Info(ctx, "Weather provider excluded from consensus",
+				slog.String("provider", p.providers[i].Name()),
+				slog.String("error", errs[i].Error()))
+			continue
+		}
+		if combined == nil {
+			first := *w
+			combined = &first
+		}
+		temperatures = append(temperatures, w.Temperature)
+		humidities = append(humidities, w.Humidity)
+		combined.Sources = append(combined.Sources, p.providers[i].Name())
+	}
+
+	if combined == nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		for _, err := range errs {
+			if errors.Is(err, provider.ErrLocationNotFound) {
+				return nil, err
+			}
+		}
+		return nil, fmt.Errorf("all weather providers failed: %w", errors.Join(errs...))
+	}
+
+	combined.Temperature = median(temperatures)
+	combined.Humidity = median(humidities)
+	return combined, nil
+}
+
+// Forecast is not combined: providers are tried in priority order as in failover mode.
+func (p *ConsensusProvider) Forecast(ctx context.Context, location string, days int) (*model.Forecast, error) {
+	return failover(ctx, p.providers, p.timeout, func(ctx context.Context, wp provider.WeatherProvider) (*model.Forecast, error) {
+		return wp.Forecast(ctx, location, days)
+	})
+}
+
+// HourlyForecast is not combined: providers are tried in priority order as in failover mode.
+func (p *ConsensusProvider) HourlyForecast(ctx context.Context, location string, from, to time.Time) (*model.HourlyForecast, error) {
+	return failover(ctx, p.providers, p.timeout, func(ctx context.Context, wp provider.WeatherProvider) (*model.HourlyForecast, error) {
+		return wp.HourlyForecast(ctx, location, from, to)
+	})
+}
+
+// History is not combined: providers are tried in priority order as in failover mode.
+func (p *ConsensusProvider) History(ctx context.Context, location string, date time.Time) (*model.History, error) {
+	return failover(ctx, p.providers, p.timeout, func(ctx context.Context, wp provider.WeatherProvider) (*model.History, error) {
+		return wp.History(ctx, location, date)
+	})
+}
+
+// AirQuality is not combined: providers are tried in priority order as in failover mode.
+func (p *ConsensusProvider) AirQuality(ctx context.Context, location string) (*model.AirQuality, error) {
+	return failover(ctx, p.providers, p.timeout, func(ctx context.Context, wp provider.WeatherProvider) (*model.AirQuality, error) {
+		return wp.AirQuality(ctx, location)
+	})
+}
+
+// Alerts is not combined: providers are tried in priority order as in failover mode.
+func (p *ConsensusProvider) Alerts(ctx context.Context, location string) (*model.Alerts, error) {
+	return failover(ctx, p.providers, p.timeout, func(ctx context.Context, wp provider.WeatherProvider) (*model.Alerts, error) {
+		return wp.Alerts(ctx, location)
+	})
+}
+
+// Astronomy is not combined: providers are tried in priority order as in failover mode.
+func (p *ConsensusProvider) Astronomy(ctx context.Context, location string, date time.Time) (*model.Astronomy, error) {
+	return failover(ctx, p.providers, p.timeout, func(ctx context.Context, wp provider.WeatherProvider) (*model.Astronomy, error) {
+		return wp.Astronomy(ctx, location, date)
+	})
+}
+
+// SearchLocations is not combined: providers are tried in priority order as in failover mode.
+func (p *ConsensusProvider) SearchLocations(ctx context.Context, query string) ([]model.Location, error) {
+	return failover(ctx, p.providers, p.timeout, func(ctx context.Context, wp provider.WeatherProvider) ([]model.Location, error) {
+		return wp.SearchLocations(ctx, query)
+	})
+}
+
+// ResolveLocation is not combined: only the primary provider is asked, as in failover mode.
+func (p *ConsensusProvider) ResolveLocation(ctx context.Context, query string) (*model.Location, error) {
+	return resolvePrimary(ctx, p.providers, p.timeout, query)
+}
+
+// resolvePrimary resolves the location through the first provider only.
+func resolvePrimary(ctx context.Context, providers []provider.WeatherProvider, timeout time.Duration, query string) (*model.Location, error) {
+	return callWithTimeout(ctx, timeout, providers[0], func(ctx context.Context, wp provider.WeatherProvider) (*model.Location, error) {
+		return wp.ResolveLocation(ctx, query)
+	})
+}
+
+// callWithTimeout invokes fn with a per-provider deadline when timeout is positive.
+func callWithTimeout[T any](ctx context.Context, timeout time.Duration, wp provider.WeatherProvider, fn func(context.Context, provider.WeatherProvider) (T, error)) (T, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return fn(ctx, wp)
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+func joinNames(providers []provider.WeatherProvider) string {
+	names := make([]string, 0, len(providers))
+	for _, wp := range providers {
+		names = append(names, wp.Name())
+	}
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,247 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"Weather-API-Application/internal/model"
+	"Weather-API-Application/internal/provider"
+	"Weather-API-Application/internal/resilience"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProvider answers CurrentWeather and ResolveLocation with a fixed result and records its calls.
+type fakeProvider struct {
+	provider.WeatherProvider
+	name    string
+	weather *model.Weather
+	err     error
+
+	mu    sync.Mutex
+	calls int
+}
+
+func (p *fakeProvider) Name() string {
+	return p.name
+}
+
+func (p *fakeProvider) CurrentWeather(ctx context.Context, location string) (*model.Weather, error) {
+	p.mu.Lock()
+	p.calls++
+	p.mu.Unlock()
+	if p.err != nil {
+		return nil, p.err
+	}
+	w := *p.weather
+	return &w, nil
+}
+
+func (p *fakeProvider) ResolveLocation(ctx context.Context, query string) (*model.Location, error) {
+	p.mu.Lock()
+	p.calls++
+	p.mu.Unlock()
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &model.Location{ID: p.name + ":1", Name: query}, nil
+}
+
+func answering(name string, temperature, humidity float64) *fakeProvider {
+	return &fakeProvider{name: name, weather: &model.Weather{
+		Temperature: temperature,
+		Humidity:    humidity,
+		Description: name + " sky",
+	}}
+}
+
+func failing(name string, err error) *fakeProvider {
+	return &fakeProvider{name: name, err: err}
+}
+
+var (
+	errUnavailable = &provider.UpstreamError{Provider: "fake", StatusCode: http.StatusServiceUnavailable}
+	errBadRequest  = &provider.UpstreamError{Provider: "fake", StatusCode: http.StatusBadRequest}
+	errOpenBreaker = &resilience.OpenError{Name: "fake", RetryAfter: time.Minute}
+)
+
+func TestFailoverProvider(t *testing.T) {
+	tests := []struct {
+		name      string
+		providers []*fakeProvider
+		wantDesc  string
+		wantErr   error
+		wantCalls []int
+	}{
+		{
+			name:      "first provider answers",
+			providers: []*fakeProvider{answering("a", 10, 50), answering("b", 20, 60)},
+			wantDesc:  "a sky",
+			wantCalls: []int{1, 0},
+		},
+		{
+			name: "transient errors fail over in order",
+			providers: []*fakeProvider{
+				failing("a", errUnavailable),
+				failing("b", provider.ErrQuotaExceeded),
+				failing("c", errOpenBreaker),
+				failing("d", provider.ErrNotSupported),
+				answering("e", 20, 60),
+			},
+			wantDesc:  "e sky",
+			wantCalls: []int{1, 1, 1, 1, 1},
+		},
+		{
+			name:      "non-transient error stops failover",
+			providers: []*fakeProvider{failing("a", provider.ErrLocationNotFound), answering("b", 20, 60)},
+			wantErr:   provider.ErrLocationNotFound,
+			wantCalls: []int{1, 0},
+		},
+		{
+			name:      "client error stops failover",
+			providers: []*fakeProvider{failing("a", errBadRequest), answering("b", 20, 60)},
+			wantErr:   errBadRequest,
+			wantCalls: []int{1, 0},
+		},
+		{
+			name:      "all providers fail",
+			providers: []*fakeProvider{failing("a", provider.ErrQuotaExceeded), failing("b", errUnavailable)},
+			wantErr:   errUnavailable,
+			wantCalls: []int{1, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			providers := make([]provider.WeatherProvider, len(tt.providers))
+			for i, p := range tt.providers {
+				providers[i] = p
+			}
+
+			weather, err := NewFailoverProvider(time.Second, providers...).CurrentWeather(context.Background(), "Kyiv")
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.wantDesc, weather.Description)
+			}
+			for i, p := range tt.providers {
+				require.Equal(t, tt.wantCalls[i], p.calls, "calls to %s", p.name)
+			}
+		})
+	}
+}
+
+func TestConsensusProvider(t *testing.T) {
+	tests := []struct {
+		name            string
+		providers       []*fakeProvider
+		wantTemperature float64
+		wantHumidity    float64
+		wantDesc        string
+		wantSources     []string
+		wantErr         error
+	}{
+		{
+			name:            "odd count takes the middle value",
+			providers:       []*fakeProvider{answering("a", 12, 70), answering("b", 10, 40), answering("c", 30, 55)},
+			wantTemperature: 12,
+			wantHumidity:    55,
+			wantDesc:        "a sky",
+			wantSources:     []string{"a", "b", "c"},
+		},
+		{
+			name: "even count averages the middle values",
+			providers: []*fakeProvider{
+				answering("a", 10, 40), answering("b", 14, 60), answering("c", 11, 50), answering("d", 40, 90),
+			},
+			wantTemperature: 12.5,
+			wantHumidity:    55,
+			wantDesc:        "a sky",
+			wantSources:     []string{"a", "b", "c", "d"},
+		},
+		{
+			name: "failed providers are excluded",
+			providers: []*fakeProvider{
+				failing("a", errUnavailable), answering("b", 10, 40), failing("c", provider.ErrQuotaExceeded), answering("d", 13, 60),
+			},
+			wantTemperature: 11.5,
+			wantHumidity:    50,
+			wantDesc:        "b sky",
+			wantSources:     []string{"b", "d"},
+		},
+		{
+			name:      "not found wins when all providers fail",
+			providers: []*fakeProvider{failing("a", errUnavailable), failing("b", provider.ErrLocationNotFound)},
+			wantErr:   provider.ErrLocationNotFound,
+		},
+		{
+			name:      "all providers fail",
+			providers: []*fakeProvider{failing("a", errUnavailable), failing("b", errOpenBreaker)},
+			wantErr:   resilience.ErrOpen,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			providers := make([]provider.WeatherProvider, len(tt.providers))
+			for i, p := range tt.providers {
+				providers[i] = p
+			}
+
+			weather, err := NewConsensusProvider(time.Second, providers...).CurrentWeather(context.Background(), "Kyiv")
+			for _, p := range tt.providers {
+				require.Equal(t, 1, p.calls, "calls to %s", p.name)
+			}
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantTemperature, weather.Temperature)
+			require.Equal(t, tt.wantHumidity, weather.Humidity)
+			require.Equal(t, tt.wantDesc, weather.Description)
+			require.Equal(t, tt.wantSources, weather.Sources)
+		})
+	}
+}
+
+func TestFailoverProviderCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	first := failing("a", errUnavailable)
+	second := answering("b", 20, 60)
+	cancel()
+
+	_, err := NewFailoverProvider(time.Second, first, second).CurrentWeather(ctx, "Kyiv")
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 0, second.calls)
+}
+
+func TestResolveLocationUsesPrimary(t *testing.T) {
+	modes := map[string]func(...provider.WeatherProvider) provider.WeatherProvider{
+		"failover": func(providers ...provider.WeatherProvider) provider.WeatherProvider {
+			return NewFailoverProvider(time.Second, providers...)
+		},
+		"consensus": func(providers ...provider.WeatherProvider) provider.WeatherProvider {
+			return NewConsensusProvider(time.Second, providers...)
+		},
+	}
+
+	for name, newProvider := range modes {
+		t.Run(name, func(t *testing.T) {
+			primary, secondary := answering("a", 10, 50), answering("b", 20, 60)
+			location, err := newProvider(primary, secondary).ResolveLocation(context.Background(), "Kyiv")
+			require.NoError(t, err)
+			require.Equal(t, "a:1", location.ID)
+
+			// A secondary would store the same place under another ID, so it is not asked
+			primary, secondary = failing("a", errUnavailable), answering("b", 20, 60)
+			_, err = newProvider(primary, secondary).ResolveLocation(context.Background(), "Kyiv")
+			require.ErrorIs(t, err, errUnavailable)
+			require.Equal(t, 0, secondary.calls)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+
+	"Weather-API-Application/internal/config"
+	"Weather-API-Application/internal/provider"
+	"Weather-API-Application/internal/quota"
+	"Weather-API-Application/internal/resilience"
+)
+
+const (
+	ModeFailover  = "failover"
+	ModeConsensus = "consensus"
+)
+
+// NewWeatherProvider builds the weather provider selected by the config.
+// Each upstream provider retries transient failures, has a circuit breaker, which is
+// added to breakers, and sends its HTTP requests through meter to enforce its budget.
+// Replayed fixtures cost nothing and are not metered.
+// The replay and record providers read and write WeatherAPI.com fixtures in cfg.WeatherFixturesDir.
+// When fallback providers are configured, the primary provider and the
+// fallbacks are combined according to cfg.WeatherProviderMode. The result is cached unless
+// cfg.WeatherCacheTTL is zero.
+func NewWeatherProvider(cfg *config.Config, breakers *resilience.Registry, meter *quota.Meter) (provider.WeatherProvider, error) {
+	wp, err := newUpstreamProvider(cfg, breakers, meter)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.WeatherCacheTTL <= 0 {
+		return wp, nil
+	}
+	return NewCachedProvider(wp, cfg.WeatherCacheTTL, cfg.WeatherCacheStaleTTL, cfg.WeatherCacheMaxStaleAge, cfg.WeatherCacheMaxEntries), nil
+}
+
+func newUpstreamProvider(cfg *config.Config, breakers *resilience.Registry, meter *quota.Meter) (provider.WeatherProvider, error) {
+	names := append([]string{cfg.WeatherProvider}, cfg.WeatherFallbackProviders...)
+	policy := resilience.Policy{
+		MaxAttempts: cfg.WeatherRetryMaxAttempts,
+		BaseDelay:   cfg.WeatherRetryBaseDelay,
+		MaxDelay:    cfg.WeatherRetryMaxDelay,
+	}
+
+	providers := make([]provider.WeatherProvider, 0, len(names))
+	for _, name := range names {
+		var transport http.RoundTripper = &quota.Transport{Base: http.DefaultTransport, Meter: meter, Provider: name}
+		switch name {
+		case ReplayName:
+			transport = &ReplayTransport{Dir: cfg.WeatherFixturesDir}
+		case RecordName:
+			transport = &quota.Transport{
+				Base:     &RecordTransport{Base: http.DefaultTransport, Dir: cfg.WeatherFixturesDir},
+				Meter:    meter,
+				Provider: name,
+			}
+		}
+		httpClient := &http.Client{
+			Timeout:   cfg.WeatherHTTPTimeout,
+			Transport: transport,
+		}
+		wp, err := newSingleProvider(cfg, name, httpClient)
+		if err != nil {
+			return nil, err
+		}
+		breaker := breakers.Register(resilience.NewBreaker(name, cfg.WeatherBreakerFailureThreshold, cfg.WeatherBreakerOpenTimeout))
+		providers = append(providers, NewResilientProvider(wp, policy, breaker))
+	}
+
+	switch cfg.WeatherProviderMode {
+	case ModeConsensus:
+		return NewConsensusProvider(cfg.WeatherProviderTimeout, providers...), nil
+	case ModeFailover:
+		if len(providers) == 1 {
+			return providers[0], nil
+		}
+		return NewFailoverProvider(cfg.WeatherProviderTimeout, providers...), nil
+	default:
+		return nil, fmt.Errorf("unknown weather provider mode: %q", cfg.WeatherProviderMode)
+	}
+}
+
+func newSingleProvider(cfg *config.Config, name string, httpClient *http.Client) (provider.WeatherProvider, error) {
+	switch name {
+	case WeatherAPIName, ReplayName, RecordName:
+		// Replay and record are WeatherAPI.com with fixture files behind the HTTP client
+		return NewWeatherAPIProvider(cfg.WeatherAPIBaseURL, cfg.WeatherApiKey, httpClient), nil
+	case OpenMeteoName:
+		return NewOpenMeteoProvider(httpClient), nil
+	case OpenWeatherMapName:
+		return NewOpenWeatherMapProvider(cfg.OpenWeatherMapApiKey, httpClient), nil
+	default:
+		return nil, fmt.Errorf("unknown weather provider: %q", name)
+	}
+}
@@ -0,0 +1,36 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// fetchJSON performs a GET request and decodes the response body into dst when the
+// status is 200 OK, or into errDst (if not nil) otherwise. The HTTP status code is returned.
+func fetchJSON(ctx context.Context, httpClient *http.Client, rawURL string, dst, errDst any) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch weather data: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		if errDst != nil {
+			// Error bodies are informational only, ignore decoding failures
+			_ = json.NewDecoder(resp.Body).Decode(errDst)
+		}
+		return resp.StatusCode, nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(dst); err != nil {
+		return resp.StatusCode, fmt.Errorf("failed to decode weather data: %w", err)
+	}
+	return resp.StatusCode, nil
+}
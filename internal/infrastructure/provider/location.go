@@ -0,0 +1,14 @@
+package provider
+
+import "Weather-API-Application/internal/model"
+
+// maxSearchResults is the maximum number of locations returned by a search.
+const maxSearchResults = 10
+
+// limitLocations truncates search results to maxSearchResults.
+func limitLocations(locations []model.Location) []model.Location {
+	if len(locations) > maxSearchResults {
+		return locations[:maxSearchResults]
+	}
+	return locations
+}
@@ -0,0 +1,447 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"Weather-API-Application/internal/model"
+	"Weather-API-Application/internal/provider"
+	"Weather-API-Application/internal/utils/airquality"
+	"Weather-API-Application/internal/utils/astronomy"
+)
+
+const (
+	OpenMeteoName          = "openmeteo"
+	openMeteoForecastURL   = "https://api.open-meteo.com/v1/forecast"
+	openMeteoGeocodingURL  = "https://geocoding-api.open-meteo.com/v1/search"
+	openMeteoArchiveURL    = "https://archive-api.open-meteo.com/v1/archive"
+	openMeteoAirQualityURL = "https://air-quality-api.open-meteo.com/v1/air-quality"
+
+	// openMeteoPastDays is how far back the forecast API serves past data;
+	// older dates are fetched from the historical archive.
+	openMeteoPastDays = 90
+)
+
+// OpenMeteoProvider fetches weather data from Open-Meteo. It does not require an API key.
+type OpenMeteoProvider struct {
+	httpClient *http.Client
+}
+
+func NewOpenMeteoProvider(httpClient *http.Client) provider.WeatherProvider {
+	return &OpenMeteoProvider{
+		httpClient: httpClient,
+	}
+}
+
+func (p *OpenMeteoProvider) Name() string {
+	return OpenMeteoName
+}
+
+// CurrentWeather resolves the location through the Open-Meteo geocoding API
+// and fetches the current conditions for its coordinates.
+func (p *OpenMeteoProvider) CurrentWeather(ctx context.Context, location string) (*model.Weather, error) {
+	geo, err := p.geocode(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	params := coordinateParams(geo)
+	params.Set("current", "temperature_2m,apparent_temperature,relative_humidity_2m,weather_code,wind_speed_10m,"+
+		"wind_direction_10m,wind_gusts_10m,pressure_msl,precipitation,cloud_cover,visibility,uv_index")
+	params.Set("timeformat", "unixtime")
+
+	var resp model.OpenMeteoForecastResponse
+	if err := p.get(ctx, openMeteoForecastURL, params, &resp); err != nil {
+		return nil, err
+	}
+
+	current := resp.Current
+	return &model.Weather{
+		Temperature:   current.Temperature2m,
+		FeelsLike:     current.ApparentTemperature,
+		Humidity:      current.RelativeHumidity2m,
+		Description:   wmoDescription(current.WeatherCode),
+		WindSpeed:     current.WindSpeed10m,
+		WindDirection: current.WindDirection10m,
+		WindGust:      current.WindGusts10m,
+		Pressure:      current.PressureMsl,
+		Precipitation: current.Precipitation,
+		CloudCover:    current.CloudCover,
+		Visibility:    current.Visibility / 1000,
+		UVIndex:       &current.UVIndex,
+		ObservedAt:    time.Unix(current.Time, 0).UTC(),
+	}, nil
+}
+
+// Forecast fetches daily aggregates for the location in its local timezone.
+func (p *OpenMeteoProvider) Forecast(ctx context.Context, location string, days int) (*model.Forecast, error) {
+	geo, err := p.geocode(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	params := coordinateParams(geo)
+	params.Set("daily", "temperature_2m_max,temperature_2m_min,precipitation_probability_max,weather_code,wind_speed_10m_max")
+	params.Set("forecast_days", strconv.Itoa(days))
+	params.Set("timezone", "auto")
+
+	var resp model.OpenMeteoForecastResponse
+	if err := p.get(ctx, openMeteoForecastURL, params, &resp); err != nil {
+		return nil, err
+	}
+
+	daily := resp.Daily
+	forecast := &model.Forecast{City: geo.Name}
+	for i, date := range daily.Time {
+		if i >= len(daily.Temperature2mMax) || i >= len(daily.Temperature2mMin) ||
+			i >= len(daily.PrecipitationProbabilityMax) || i >= len(daily.WeatherCode) || i >= len(daily.WindSpeed10mMax) {
+			break
+		}
+		forecast.Days = append(forecast.Days, model.ForecastDay{
+			Date:           date,
+			MinTemperature: daily.Temperature2mMin[i],
+			MaxTemperature: daily.Temperature2mMax[i],
+			ChanceOfRain:   daily.PrecipitationProbabilityMax[i],
+			Condition:      wmoDescription(daily.WeatherCode[i]),
+			MaxWindSpeed:   daily.WindSpeed10mMax[i],
+		})
+	}
+	return forecast, nil
+}
+
+// HourlyForecast fetches hourly values for the window. Times are requested and returned in UTC.
+func (p *OpenMeteoProvider) HourlyForecast(ctx context.Context, location string, from, to time.Time) (*model.HourlyForecast, error) {
+	geo, err := p.geocode(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	const hourLayout = "2006-01-02T15:04"
+	params := coordinateParams(geo)
+	params.Set("hourly", "temperature_2m,precipitation,precipitation_probability,weather_code")
+	params.Set("start_hour", from.UTC().Format(hourLayout))
+	params.Set("end_hour", to.UTC().Format(hourLayout))
+	params.Set("timezone", "GMT")
+	params.Set("timeformat", "unixtime")
+
+	var resp model.OpenMeteoForecastResponse
+	if err := p.get(ctx, openMeteoForecastURL, params, &resp); err != nil {
+		return nil, err
+	}
+
+	hourly := resp.Hourly
+	forecast := &model.HourlyForecast{City: geo.Name, Hours: []model.ForecastHour{}}
+	for i, ts := range hourly.Time {
+		if i >= len(hourly.Temperature2m) || i >= len(hourly.Precipitation) ||
+			i >= len(hourly.PrecipitationProbability) || i >= len(hourly.WeatherCode) {
+			break
+		}
+		t := time.Unix(ts, 0).UTC()
+		if !inWindow(t, from, to) {
+			continue
+		}
+		forecast.Hours = append(forecast.Hours, model.ForecastHour{
+			Time:          t,
+			Temperature:   hourly.Temperature2m[i],
+			Precipitation: hourly.Precipitation[i],
+			ChanceOfRain:  hourly.PrecipitationProbability[i],
+			Condition:     wmoDescription(hourly.WeatherCode[i]),
+		})
+	}
+	return forecast, nil
+}
+
+// History fetches hourly observations for the date (UTC) and summarizes them.
+func (p *OpenMeteoProvider) History(ctx context.Context, location string, date time.Time) (*model.History, error) {
+	geo, err := p.geocode(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL := openMeteoForecastURL
+	if time.Since(date) > openMeteoPastDays*24*time.Hour {
+		baseURL = openMeteoArchiveURL
+	}
+
+	day := date.Format(time.DateOnly)
+	params := coordinateParams(geo)
+	params.Set("hourly", "temperature_2m,relative_humidity_2m,weather_code")
+	params.Set("start_date", day)
+	params.Set("end_date", day)
+	params.Set("timezone", "GMT")
+	params.Set("timeformat", "unixtime")
+
+	var resp model.OpenMeteoForecastResponse
+	if err := p.get(ctx, baseURL, params, &resp); err != nil {
+		return nil, err
+	}
+
+	hourly := resp.Hourly
+	history := &model.History{City: geo.Name, Date: day, Source: model.HistorySourceProvider, Hours: []model.HistoryHour{}}
+	codes := map[int]int{}
+	var humiditySum, temperatureSum float64
+	for i, ts := range hourly.Time {
+		if i >= len(hourly.Temperature2m) || i >= len(hourly.RelativeHumidity2m) || i >= len(hourly.WeatherCode) {
+			break
+		}
+		temperature := hourly.Temperature2m[i]
+		if i == 0 || temperature < history.MinTemperature {
+			history.MinTemperature = temperature
+		}
+		if i == 0 || temperature > history.MaxTemperature {
+			history.MaxTemperature = temperature
+		}
+		temperatureSum += temperature
+		humiditySum += hourly.RelativeHumidity2m[i]
+		codes[hourly.WeatherCode[i]]++
+
+		history.Hours = append(history.Hours, model.HistoryHour{
+			Time:        time.Unix(ts, 0).UTC(),
+			Temperature: temperature,
+			Humidity:    hourly.RelativeHumidity2m[i],
+			Condition:   wmoDescription(hourly.WeatherCode[i]),
+		})
+	}
+	if len(history.Hours) == 0 {
+		return nil, provider.ErrNoData
+	}
+
+	history.AvgTemperature = temperatureSum / float64(len(history.Hours))
+	history.AvgHumidity = humiditySum / float64(len(history.Hours))
+	// Ties are resolved in favour of the higher, i.e. more severe, WMO code
+	mostFrequent := -1
+	for code, count := range codes {
+		if mostFrequent == -1 || count > codes[mostFrequent] || (count == codes[mostFrequent] && code > mostFrequent) {
+			mostFrequent = code
+		}
+	}
+	history.Condition = wmoDescription(mostFrequent)
+	return history, nil
+}
+
+// AirQuality fetches current pollutant concentrations from the Open-Meteo air quality API.
+// Indices are derived from PM2.5.
+func (p *OpenMeteoProvider) AirQuality(ctx context.Context, location string) (*model.AirQuality, error) {
+	geo, err := p.geocode(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	params := coordinateParams(geo)
+	params.Set("current", "pm2_5,pm10,ozone,nitrogen_dioxide")
+
+	var resp model.OpenMeteoAirQualityResponse
+	if err := p.get(ctx, openMeteoAirQualityURL, params, &resp); err != nil {
+		return nil, err
+	}
+
+	airQuality := &model.AirQuality{
+		PM25: resp.Current.PM25,
+		PM10: resp.Current.PM10,
+		O3:   resp.Current.Ozone,
+		NO2:  resp.Current.NitrogenDioxide,
+	}
+	airquality.Complete(airQuality)
+	return airQuality, nil
+}
+
+// Alerts is not supported: Open-Meteo does not provide weather alerts.
+func (p *OpenMeteoProvider) Alerts(ctx context.Context, location string) (*model.Alerts, error) {
+	return nil, provider.ErrNotSupported
+}
+
+// Astronomy fetches sunrise and sunset for the location in its local timezone.
+// Open-Meteo has no moon data, so the moon phase is calculated locally and moonrise/moonset are left empty.
+func (p *OpenMeteoProvider) Astronomy(ctx context.Context, location string, date time.Time) (*model.Astronomy, error) {
+	geo, err := p.geocode(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL := openMeteoForecastURL
+	if time.Since(date) > openMeteoPastDays*24*time.Hour {
+		baseURL = openMeteoArchiveURL
+	}
+
+	day := date.Format(time.DateOnly)
+	params := coordinateParams(geo)
+	params.Set("daily", "sunrise,sunset")
+	params.Set("start_date", day)
+	params.Set("end_date", day)
+	params.Set("timezone", "auto")
+
+	var resp model.OpenMeteoForecastResponse
+	if err := p.get(ctx, baseURL, params, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Daily.Sunrise) == 0 || len(resp.Daily.Sunset) == 0 {
+		return nil, provider.ErrNoData
+	}
+
+	loc, err := time.LoadLocation(resp.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	phase, illumination := astronomy.MoonPhase(time.Date(date.Year(), date.Month(), date.Day(), 12, 0, 0, 0, time.UTC))
+	return &model.Astronomy{
+		City:             geo.Name,
+		Date:             day,
+		Source:           model.AstronomySourceProvider,
+		Sunrise:          parseLocalTime(resp.Daily.Sunrise[0], loc),
+		Sunset:           parseLocalTime(resp.Daily.Sunset[0], loc),
+		MoonPhase:        phase,
+		MoonIllumination: illumination,
+	}, nil
+}
+
+// parseLocalTime parses an Open-Meteo ISO 8601 local time such as "2025-06-21T04:47".
+func parseLocalTime(value string, loc *time.Location) *time.Time {
+	t, err := time.ParseInLocation("2006-01-02T15:04", value, loc)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// SearchLocations calls the Open-Meteo geocoding API.
+func (p *OpenMeteoProvider) SearchLocations(ctx context.Context, query string) ([]model.Location, error) {
+	params := url.Values{}
+	params.Set("name", query)
+	params.Set("count", strconv.Itoa(maxSearchResults))
+
+	var resp model.OpenMeteoGeocodingResponse
+	if err := p.get(ctx, openMeteoGeocodingURL, params, &resp); err != nil {
+		return nil, err
+	}
+
+	locations := make([]model.Location, 0, len(resp.Results))
+	for _, r := range resp.Results {
+		locations = append(locations, model.Location{
+			ID:       OpenMeteoName + ":" + strconv.FormatInt(r.ID, 10),
+			Name:     r.Name,
+			Region:   r.Admin1,
+			Country:  r.Country,
+			Lat:      r.Latitude,
+			Lon:      r.Longitude,
+			Timezone: r.Timezone,
+		})
+	}
+	return limitLocations(locations), nil
+}
+
+// ResolveLocation returns the best geocoding match. Open-Meteo has no reverse geocoding,
+// so "lat,lon" queries resolve to the coordinates themselves with the timezone of that point.
+func (p *OpenMeteoProvider) ResolveLocation(ctx context.Context, query string) (*model.Location, error) {
+	geo, err := p.geocode(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	location := &model.Location{
+		ID:       OpenMeteoName + ":" + strconv.FormatInt(geo.ID, 10),
+		Name:     geo.Name,
+		Region:   geo.Admin1,
+		Country:  geo.Country,
+		Lat:      geo.Latitude,
+		Lon:      geo.Longitude,
+		Timezone: geo.Timezone,
+	}
+	if geo.ID == 0 {
+		location.ID = OpenMeteoName + ":" + model.Coordinates{Lat: geo.Latitude, Lon: geo.Longitude}.String()
+
+		params := coordinateParams(geo)
+		params.Set("timezone", "auto")
+
+		var resp model.OpenMeteoForecastResponse
+		if err := p.get(ctx, openMeteoForecastURL, params, &resp); err != nil {
+			return nil, err
+		}
+		location.Timezone = resp.Timezone
+	}
+	return location, nil
+}
+
+// geocode returns the best match for the given location name.
+// "lat,lon" queries are used as they are, without calling the geocoding API.
+func (p *OpenMeteoProvider) geocode(ctx context.Context, location string) (*model.OpenMeteoGeocodingResult, error) {
+	if coords, ok := model.ParseCoordinates(location); ok {
+		return &model.OpenMeteoGeocodingResult{
+			Name:      location,
+			Latitude:  coords.Lat,
+			Longitude: coords.Lon,
+		}, nil
+	}
+
+	params := url.Values{}
+	params.Set("name", location)
+	params.Set("count", "1")
+
+	var resp model.OpenMeteoGeocodingResponse
+	if err := p.get(ctx, openMeteoGeocodingURL, params, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Results) == 0 {
+		return nil, provider.ErrLocationNotFound
+	}
+	return &resp.Results[0], nil
+}
+
+func coordinateParams(geo *model.OpenMeteoGeocodingResult) url.Values {
+	params := url.Values{}
+	params.Set("latitude", strconv.FormatFloat(geo.Latitude, 'f', -1, 64))
+	params.Set("longitude", strconv.FormatFloat(geo.Longitude, 'f', -1, 64))
+	return params
+}
+
+func (p *OpenMeteoProvider) get(ctx context.Context, baseURL string, params url.Values, dst any) error {
+	status, err := fetchJSON(ctx, p.httpClient, baseURL+"?"+params.Encode(), dst, nil)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return &provider.UpstreamError{Provider: OpenMeteoName, StatusCode: status}
+	}
+	return nil
+}
+
+// wmoDescriptions maps WMO weather interpretation codes used by Open-Meteo to text.
+var wmoDescriptions = map[int]string{
+	0:  "Clear sky",
+	1:  "Mainly clear",
+	2:  "Partly cloudy",
+	3:  "Overcast",
+	45: "Fog",
+	48: "Depositing rime fog",
+	51: "Light drizzle",
+	53: "Moderate drizzle",
+	55: "Dense drizzle",
+	56: "Light freezing drizzle",
+	57: "Dense freezing drizzle",
+	61: "Slight rain",
+	63: "Moderate rain",
+	65: "Heavy rain",
+	66: "Light freezing rain",
+	67: "Heavy freezing rain",
+	71: "Slight snow fall",
+	73: "Moderate snow fall",
+	75: "Heavy snow fall",
+	77: "Snow grains",
+	80: "Slight rain showers",
+	81: "Moderate rain showers",
+	82: "Violent rain showers",
+	85: "Slight snow showers",
+	86: "Heavy snow showers",
+	95: "Thunderstorm",
+	96: "Thunderstorm with slight hail",
+	99: "Thunderstorm with heavy hail",
+}
+
+func wmoDescription(code int) string {
+	if d, ok := wmoDescriptions[code]; ok {
+		return d
+	}
+	return "Unknown"
+}
@@ -0,0 +1,110 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"Weather-API-Application/internal/provider"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newOpenMeteoServer(t *testing.T, forecastStatus int) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/search", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("name") != "Kyiv" {
+			_, _ = w.Write([]byte(`{}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"results":[{"id":703448,"name":"Kyiv","latitude":50.45,"longitude":30.52,"country":"Ukraine","timezone":"Europe/Kyiv"}]}`))
+	})
+	mux.HandleFunc("GET /v1/forecast", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "50.45", r.URL.Query().Get("latitude"))
+		require.Equal(t, "30.52", r.URL.Query().Get("longitude"))
+		if forecastStatus != http.StatusOK {
+			w.WriteHeader(forecastStatus)
+			return
+		}
+		_, _ = w.Write([]byte(`{
+			"current":{"time":1717236000,"temperature_2m":18.4,"apparent_temperature":17.9,"relative_humidity_2m":64,
+				"weather_code":61,"wind_speed_10m":12.6,"wind_direction_10m":220,"wind_gusts_10m":25.2,
+				"pressure_msl":1012.3,"precipitation":0.4,"cloud_cover":75,"visibility":24140,"uv_index":3.5},
+			"daily":{"time":["2024-06-01","2024-06-02","2024-06-03"],"temperature_2m_max":[22.1,24.3,20.5],
+				"temperature_2m_min":[12.4,13.0,11.8],"precipitation_probability_max":[40,10,85],
+				"weather_code":[3,0,95],"wind_speed_10m_max":[18.0,9.4]}
+		}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOpenMeteoMapping(t *testing.T) {
+	server := newOpenMeteoServer(t, http.StatusOK)
+	p := NewOpenMeteoProvider(&http.Client{Transport: redirectTransport{server: server}})
+	ctx := context.Background()
+
+	weather, err := p.CurrentWeather(ctx, "Kyiv")
+	require.NoError(t, err)
+	require.Equal(t, 18.4, weather.Temperature)
+	require.Equal(t, 17.9, weather.FeelsLike)
+	require.Equal(t, "Slight rain", weather.Description)
+	require.Equal(t, 220, weather.WindDirection)
+	// Open-Meteo reports visibility in metres
+	require.Equal(t, 24.14, weather.Visibility)
+	require.NotNil(t, weather.UVIndex)
+	require.Equal(t, 3.5, *weather.UVIndex)
+	require.Equal(t, time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC), weather.ObservedAt)
+
+	forecast, err := p.Forecast(ctx, "Kyiv", 3)
+	require.NoError(t, err)
+	require.Equal(t, "Kyiv", forecast.City)
+	// The last day is dropped because the upstream arrays differ in length
+	require.Len(t, forecast.Days, 2)
+	require.Equal(t, "2024-06-01", forecast.Days[0].Date)
+	require.Equal(t, 12.4, forecast.Days[0].MinTemperature)
+	require.Equal(t, 22.1, forecast.Days[0].MaxTemperature)
+	require.Equal(t, 40.0, forecast.Days[0].ChanceOfRain)
+	require.Equal(t, "Overcast", forecast.Days[0].Condition)
+	require.Equal(t, "Clear sky", forecast.Days[1].Condition)
+
+	// Coordinates are used without geocoding
+	weather, err = p.CurrentWeather(ctx, "50.45,30.52")
+	require.NoError(t, err)
+	require.Equal(t, 18.4, weather.Temperature)
+}
+
+func TestOpenMeteoErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		location       string
+		forecastStatus int
+		wantErr        error
+		wantStatus     int
+	}{
+		{name: "unknown location", location: "Atlantis", forecastStatus: http.StatusOK, wantErr: provider.ErrLocationNotFound},
+		{name: "upstream error", location: "Kyiv", forecastStatus: http.StatusInternalServerError, wantStatus: http.StatusInternalServerError},
+		{name: "rate limited", location: "Kyiv", forecastStatus: http.StatusTooManyRequests, wantStatus: http.StatusTooManyRequests},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newOpenMeteoServer(t, tt.forecastStatus)
+			p := NewOpenMeteoProvider(&http.Client{Transport: redirectTransport{server: server}})
+
+			_, err := p.CurrentWeather(context.Background(), tt.location)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			var upstreamErr *provider.UpstreamError
+			require.ErrorAs(t, err, &upstreamErr)
+			require.Equal(t, OpenMeteoName, upstreamErr.Provider)
+			require.Equal(t, tt.wantStatus, upstreamErr.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,296 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"Weather-API-Application/internal/model"
+	"Weather-API-Application/internal/provider"
+	"Weather-API-Application/internal/utils/airquality"
+)
+
+const (
+	OpenWeatherMapName    = "openweathermap"
+	openWeatherMapBaseURL = "https://api.openweathermap.org/data/2.5"
+	openWeatherMapGeoURL  = "https://api.openweathermap.org/geo/1.0"
+)
+
+// OpenWeatherMapProvider fetches weather data from OpenWeatherMap.
+type OpenWeatherMapProvider struct {
+	apiKey     string
+	httpClient *http.Client
+}
+
+func NewOpenWeatherMapProvider(apiKey string, httpClient *http.Client) provider.WeatherProvider {
+	return &OpenWeatherMapProvider{
+		apiKey:     apiKey,
+		httpClient: httpClient,
+	}
+}
+
+func (p *OpenWeatherMapProvider) Name() string {
+	return OpenWeatherMapName
+}
+
+// CurrentWeather calls the OpenWeatherMap current weather endpoint in metric units.
+func (p *OpenWeatherMapProvider) CurrentWeather(ctx context.Context, location string) (*model.Weather, error) {
+	params := locationParams(location)
+
+	var resp model.OpenWeatherMapResponse
+	if err := p.get(ctx, "weather", params, &resp); err != nil {
+		return nil, err
+	}
+
+	description := ""
+	if len(resp.Weather) > 0 {
+		description = capitalize(resp.Weather[0].Description)
+	}
+
+	// Wind is reported in m/s and visibility in metres; OpenWeatherMap has no current UV index.
+	return &model.Weather{
+		Temperature:   resp.Main.Temp,
+		FeelsLike:     resp.Main.FeelsLike,
+		Humidity:      resp.Main.Humidity,
+		Description:   description,
+		WindSpeed:     resp.Wind.Speed * 3.6,
+		WindDirection: resp.Wind.Deg,
+		WindGust:      resp.Wind.Gust * 3.6,
+		Pressure:      resp.Main.Pressure,
+		Precipitation: resp.Rain.OneHour + resp.Snow.OneHour,
+		CloudCover:    resp.Clouds.All,
+		Visibility:    resp.Visibility / 1000,
+		ObservedAt:    time.Unix(resp.Dt, 0).UTC(),
+	}, nil
+}
+
+// Forecast aggregates the OpenWeatherMap 5 day / 3 hour forecast into daily values.
+// At most 5 days are available.
+func (p *OpenWeatherMapProvider) Forecast(ctx context.Context, location string, days int) (*model.Forecast, error) {
+	params := locationParams(location)
+
+	var resp model.OpenWeatherMapForecastResponse
+	if err := p.get(ctx, "forecast", params, &resp); err != nil {
+		return nil, err
+	}
+
+	tz := time.FixedZone("", resp.City.Timezone)
+	forecast := &model.Forecast{City: resp.City.Name}
+	conditionCounts := map[string]int{}
+	var day *model.ForecastDay
+
+	for _, item := range resp.List {
+		date := time.Unix(item.Dt, 0).In(tz).Format(time.DateOnly)
+		if day == nil || day.Date != date {
+			if len(forecast.Days) == days {
+				break
+			}
+			forecast.Days = append(forecast.Days, model.ForecastDay{
+				Date:           date,
+				MinTemperature: item.Main.TempMin,
+				MaxTemperature: item.Main.TempMax,
+			})
+			day = &forecast.Days[len(forecast.Days)-1]
+			clear(conditionCounts)
+		}
+
+		day.MinTemperature = min(day.MinTemperature, item.Main.TempMin)
+		day.MaxTemperature = max(day.MaxTemperature, item.Main.TempMax)
+		day.ChanceOfRain = max(day.ChanceOfRain, item.Pop*100)
+		// OpenWeatherMap reports wind speed in m/s with metric units
+		day.MaxWindSpeed = max(day.MaxWindSpeed, item.Wind.Speed*3.6)
+
+		if len(item.Weather) > 0 {
+			condition := capitalize(item.Weather[0].Description)
+			conditionCounts[condition]++
+			if day.Condition == "" || conditionCounts[condition] > conditionCounts[day.Condition] {
+				day.Condition = condition
+			}
+		}
+	}
+	return forecast, nil
+}
+
+// HourlyForecast returns the 3-hourly OpenWeatherMap forecast points inside the window.
+func (p *OpenWeatherMapProvider) HourlyForecast(ctx context.Context, location string, from, to time.Time) (*model.HourlyForecast, error) {
+	params := locationParams(location)
+
+	var resp model.OpenWeatherMapForecastResponse
+	if err := p.get(ctx, "forecast", params, &resp); err != nil {
+		return nil, err
+	}
+
+	forecast := &model.HourlyForecast{City: resp.City.Name, Hours: []model.ForecastHour{}}
+	for _, item := range resp.List {
+		t := time.Unix(item.Dt, 0).UTC()
+		if !inWindow(t, from, to) {
+			continue
+		}
+		condition := ""
+		if len(item.Weather) > 0 {
+			condition = capitalize(item.Weather[0].Description)
+		}
+		forecast.Hours = append(forecast.Hours, model.ForecastHour{
+			Time:          t,
+			Temperature:   (item.Main.TempMin + item.Main.TempMax) / 2,
+			Precipitation: item.Rain.ThreeHours,
+			ChanceOfRain:  item.Pop * 100,
+			Condition:     condition,
+		})
+	}
+	return forecast, nil
+}
+
+// History is not available on the free OpenWeatherMap plans.
+func (p *OpenWeatherMapProvider) History(ctx context.Context, location string, date time.Time) (*model.History, error) {
+	return nil, provider.ErrNotSupported
+}
+
+// AirQuality looks up the location coordinates through the current weather endpoint
+// and fetches pollutant concentrations from the air pollution API. Indices are derived from PM2.5.
+func (p *OpenWeatherMapProvider) AirQuality(ctx context.Context, location string) (*model.AirQuality, error) {
+	params := locationParams(location)
+
+	var weather model.OpenWeatherMapResponse
+	if err := p.get(ctx, "weather", params, &weather); err != nil {
+		return nil, err
+	}
+
+	params = url.Values{}
+	params.Set("lat", strconv.FormatFloat(weather.Coord.Lat, 'f', -1, 64))
+	params.Set("lon", strconv.FormatFloat(weather.Coord.Lon, 'f', -1, 64))
+
+	var resp model.OpenWeatherMapAirPollutionResponse
+	if err := p.get(ctx, "air_pollution", params, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.List) == 0 {
+		return nil, provider.ErrNoData
+	}
+
+	components := resp.List[0].Components
+	airQuality := &model.AirQuality{
+		PM25: components.PM25,
+		PM10: components.PM10,
+		O3:   components.O3,
+		NO2:  components.NO2,
+	}
+	airquality.Complete(airQuality)
+	return airQuality, nil
+}
+
+// Alerts is not supported: Alerts require the paid OpenWeatherMap One Call API.
+func (p *OpenWeatherMapProvider) Alerts(ctx context.Context, location string) (*model.Alerts, error) {
+	return nil, provider.ErrNotSupported
+}
+
+// Astronomy is not supported: the free OpenWeatherMap plans only report today's sunrise and sunset.
+func (p *OpenWeatherMapProvider) Astronomy(ctx context.Context, location string, date time.Time) (*model.Astronomy, error) {
+	return nil, provider.ErrNotSupported
+}
+
+// SearchLocations calls the OpenWeatherMap direct geocoding API. It does not assign
+// location IDs, so they are derived from the coordinates.
+func (p *OpenWeatherMapProvider) SearchLocations(ctx context.Context, query string) ([]model.Location, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("limit", strconv.Itoa(maxSearchResults))
+
+	var resp []model.OpenWeatherMapGeocodingResult
+	if err := p.fetch(ctx, openWeatherMapGeoURL+"/direct", params, &resp); err != nil {
+		return nil, err
+	}
+
+	locations := make([]model.Location, 0, len(resp))
+	for _, r := range resp {
+		coords := model.Coordinates{Lat: r.Lat, Lon: r.Lon}
+		locations = append(locations, model.Location{
+			ID:      OpenWeatherMapName + ":" + coords.String(),
+			Name:    r.Name,
+			Region:  r.State,
+			Country: r.Country,
+			Lat:     r.Lat,
+			Lon:     r.Lon,
+		})
+	}
+	return limitLocations(locations), nil
+}
+
+// ResolveLocation returns the best match of the OpenWeatherMap geocoding API, using reverse
+// geocoding for "lat,lon" queries. OpenWeatherMap does not report IANA timezones.
+func (p *OpenWeatherMapProvider) ResolveLocation(ctx context.Context, query string) (*model.Location, error) {
+	endpoint := "/direct"
+	if _, ok := model.ParseCoordinates(query); ok {
+		endpoint = "/reverse"
+	}
+	params := locationParams(query)
+	params.Set("limit", "1")
+
+	var resp []model.OpenWeatherMapGeocodingResult
+	if err := p.fetch(ctx, openWeatherMapGeoURL+endpoint, params, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp) == 0 {
+		return nil, provider.ErrLocationNotFound
+	}
+
+	r := resp[0]
+	return &model.Location{
+		ID:      OpenWeatherMapName + ":" + model.Coordinates{Lat: r.Lat, Lon: r.Lon}.String(),
+		Name:    r.Name,
+		Region:  r.State,
+		Country: r.Country,
+		Lat:     r.Lat,
+		Lon:     r.Lon,
+	}, nil
+}
+
+// locationParams selects the location by coordinates for "lat,lon" queries and by name otherwise.
+func locationParams(location string) url.Values {
+	params := url.Values{}
+	if coords, ok := model.ParseCoordinates(location); ok {
+		params.Set("lat", strconv.FormatFloat(coords.Lat, 'f', -1, 64))
+		params.Set("lon", strconv.FormatFloat(coords.Lon, 'f', -1, 64))
+		return params
+	}
+	params.Set("q", location)
+	return params
+}
+
+func (p *OpenWeatherMapProvider) get(ctx context.Context, endpoint string, params url.Values, dst any) error {
+	return p.fetch(ctx, openWeatherMapBaseURL+"/"+endpoint, params, dst)
+}
+
+func (p *OpenWeatherMapProvider) fetch(ctx context.Context, baseURL string, params url.Values, dst any) error {
+	params.Set("appid", p.apiKey)
+	params.Set("units", "metric")
+	rawURL := baseURL + "?" + params.Encode()
+
+	status, err := fetchJSON(ctx, p.httpClient, rawURL, dst, nil)
+	if err != nil {
+		return err
+	}
+	switch status {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return provider.ErrLocationNotFound
+	default:
+		return &provider.UpstreamError{Provider: OpenWeatherMapName, StatusCode: status}
+	}
+}
+
+// capitalize upper-cases the first letter of OpenWeatherMap's lower-case descriptions.
+func capitalize(s string) string {
+	s = strings.TrimSpace(s)
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
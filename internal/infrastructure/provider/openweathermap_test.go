@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"Weather-API-Application/internal/provider"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newOpenWeatherMapServer(t *testing.T, status int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		require.Equal(t, "secret", query.Get("appid"))
+		require.Equal(t, "metric", query.Get("units"))
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(`{"cod":"404","message":"city not found"}`))
+			return
+		}
+
+		switch r.URL.Path {
+		case "/data/2.5/weather":
+			_, _ = w.Write([]byte(`{"name":"Kyiv","dt":1717236000,
+				"main":{"temp":18.4,"feels_like":17.9,"humidity":64,"pressure":1012},
+				"weather":[{"id":500,"description":"light rain"}],
+				"wind":{"speed":5,"deg":220,"gust":10},"clouds":{"all":75},"visibility":10000,
+				"rain":{"1h":0.4},"snow":{"1h":0.1}}`))
+		case "/data/2.5/forecast":
+			// Three-hour points over two days in UTC+3
+			_, _ = w.Write([]byte(`{"city":{"name":"Kyiv","timezone":10800},"list":[
+				{"dt":1717189200,"main":{"temp_min":12.0,"temp_max":14.0},"pop":0.2,"wind":{"speed":2},"weather":[{"description":"clear sky"}]},
+				{"dt":1717200000,"main":{"temp_min":15.0,"temp_max":19.5},"pop":0.6,"wind":{"speed":4},"weather":[{"description":"light rain"}]},
+				{"dt":1717210800,"main":{"temp_min":16.0,"temp_max":21.0},"pop":0.1,"wind":{"speed":3},"weather":[{"description":"light rain"}]},
+				{"dt":1717275600,"main":{"temp_min":11.0,"temp_max":13.0},"pop":0,"wind":{"speed":1},"weather":[{"description":"overcast clouds"}]}
+			]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOpenWeatherMapMapping(t *testing.T) {
+	server := newOpenWeatherMapServer(t, http.StatusOK)
+	p := NewOpenWeatherMapProvider("secret", &http.Client{Transport: redirectTransport{server: server}})
+	ctx := context.Background()
+
+	weather, err := p.CurrentWeather(ctx, "Kyiv")
+	require.NoError(t, err)
+	require.Equal(t, 18.4, weather.Temperature)
+	require.Equal(t, 17.9, weather.FeelsLike)
+	require.Equal(t, "Light rain", weather.Description)
+	// Wind is converted from m/s to km/h and visibility from metres to kilometres
+	require.Equal(t, 18.0, weather.WindSpeed)
+	require.Equal(t, 36.0, weather.WindGust)
+	require.Equal(t, 10.0, weather.Visibility)
+	require.InDelta(t, 0.5, weather.Precipitation, 1e-9)
+	require.Nil(t, weather.UVIndex)
+	require.Equal(t, time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC), weather.ObservedAt)
+
+	forecast, err := p.Forecast(ctx, "Kyiv", 5)
+	require.NoError(t, err)
+	require.Equal(t, "Kyiv", forecast.City)
+	require.Len(t, forecast.Days, 2)
+	today := forecast.Days[0]
+	require.Equal(t, "2024-06-01", today.Date)
+	require.Equal(t, 12.0, today.MinTemperature)
+	require.Equal(t, 21.0, today.MaxTemperature)
+	require.InDelta(t, 60.0, today.ChanceOfRain, 1e-9)
+	require.Equal(t, 14.4, today.MaxWindSpeed)
+	require.Equal(t, "Light rain", today.Condition)
+	require.Equal(t, "Overcast clouds", forecast.Days[1].Condition)
+
+	forecast, err = p.Forecast(ctx, "Kyiv", 1)
+	require.NoError(t, err)
+	require.Len(t, forecast.Days, 1)
+}
+
+func TestOpenWeatherMapErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		wantErr    error
+		wantStatus int
+	}{
+		{name: "unknown location", status: http.StatusNotFound, wantErr: provider.ErrLocationNotFound},
+		{name: "invalid key", status: http.StatusUnauthorized, wantStatus: http.StatusUnauthorized},
+		{name: "upstream error", status: http.StatusBadGateway, wantStatus: http.StatusBadGateway},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newOpenWeatherMapServer(t, tt.status)
+			p := NewOpenWeatherMapProvider("secret", &http.Client{Transport: redirectTransport{server: server}})
+
+			_, err := p.CurrentWeather(context.Background(), "Kyiv")
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			var upstreamErr *provider.UpstreamError
+			require.ErrorAs(t, err, &upstreamErr)
+			require.Equal(t, OpenWeatherMapName, upstreamErr.Provider)
+			require.Equal(t, tt.wantStatus, upstreamErr.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"Weather-API-Application/internal/logger"
+)
+
+const (
+	// ReplayName selects WeatherAPI.com responses replayed from fixture files, without network access.
+	ReplayName = "replay"
+	// RecordName selects the live WeatherAPI.com API, saving its responses as fixture files.
+	RecordName = "record"
+
+	// defaultFixture is replayed for requests without a fixture of their own.
+	defaultFixture = "default"
+)
+
+// fixture is a recorded upstream response.
+type fixture struct {
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body"`
+}
+
+// ReplayTransport is an http.RoundTripper that serves recorded responses from Dir instead of
+// calling the upstream API. Requests are looked up in <Dir>/<endpoint>/<query>.json, then in
+// <Dir>/<endpoint>/default.json. Requests without a fixture get a WeatherAPI.com
+// "No matching location found" error.
+type ReplayTransport struct {
+	Dir string
+}
+
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	endpoint, name := fixtureName(req)
+	for _, candidate := range []string{name, defaultFixture} {
+		data, err := os.ReadFile(filepath.Join(t.Dir, endpoint, candidate+".json"))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixture: %w", err)
+		}
+
+		var f fixture
+		if err := json.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("failed to decode fixture %s/%s: %w", endpoint, candidate, err)
+		}
+		return fixtureResponse(req, f.Status, f.Body), nil
+	}
+
+	logger.Info(req.Context(), "No fixture recorded for request",
+		slog.String("endpoint", endpoint),
+		slog.String("query", name))
+	body := fmt.Sprintf(`{"error":{"code":%d,"message":"No matching location found."}}`, weatherAPINoLocationCode)
+	return fixtureResponse(req, http.StatusBadRequest, []byte(body)), nil
+}
+
+// RecordTransport is an http.RoundTripper that forwards requests to Base and saves the
+// responses to Dir in the layout read by ReplayTransport. The API key is not recorded.
+type RecordTransport struct {
+	Base http.RoundTripper
+	Dir  string
+}
+
+func (t *RecordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	endpoint, name := fixtureName(req)
+	if err := t.save(endpoint, name, fixture{Status: resp.StatusCode, Body: body}); err != nil {
+		// Recording is best effort, the live response is returned either way
+		logger.Error(req.Context(), fmt.Errorf("failed to record fixture %s/%s: %w", endpoint, name, err))
+	}
+	return resp, nil
+}
+
+func (t *RecordTransport) save(endpoint, name string, f fixture) error {
+	if !json.Valid(f.Body) {
+		return fmt.Errorf("response is not JSON")
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(t.Dir, endpoint)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, name+".json"), data, 0o644)
+}
+
+// fixtureName returns the endpoint of a request, such as "current", and the name of its fixture:
+// the escaped query without the API key, such as "aqi=no&q=kyiv".
+func fixtureName(req *http.Request) (string, string) {
+	endpoint := strings.TrimSuffix(path.Base(req.URL.Path), ".json")
+	query := req.URL.Query()
+	query.Del("key")
+	return endpoint, strings.ToLower(query.Encode())
+}
+
+func fixtureResponse(req *http.Request, status int, body []byte) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"Weather-API-Application/internal/provider"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testBaseURL = "https://api.weatherapi.com/v1"
+
+// redirectTransport sends every request to a test server.
+type redirectTransport struct {
+	server *httptest.Server
+}
+
+func (t redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = "http"
+	req.URL.Host = t.server.Listener.Addr().String()
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		require.Equal(t, "secret", r.URL.Query().Get("key"))
+		if r.URL.Query().Get("q") != "Kyiv" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":{"code":1006,"message":"No matching location found."}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"current":{"temp_c":11.3,"humidity":71,"condition":{"text":"Partly cloudy"}}}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	recorder := NewWeatherAPIProvider(testBaseURL, "secret", &http.Client{
+		Transport: &RecordTransport{Base: redirectTransport{server: server}, Dir: dir},
+	})
+	weather, err := recorder.CurrentWeather(ctx, "Kyiv")
+	require.NoError(t, err)
+	require.Equal(t, 11.3, weather.Temperature)
+	_, err = recorder.CurrentWeather(ctx, "Atlantis")
+	require.ErrorIs(t, err, provider.ErrLocationNotFound)
+	require.Equal(t, 2, calls)
+
+	// Replay needs neither the API key nor the server, and matches queries case-insensitively
+	replayer := NewWeatherAPIProvider(testBaseURL, "", &http.Client{Transport: &ReplayTransport{Dir: dir}})
+	weather, err = replayer.CurrentWeather(ctx, "kyiv")
+	require.NoError(t, err)
+	require.Equal(t, 11.3, weather.Temperature)
+	require.Equal(t, "Partly cloudy", weather.Description)
+	_, err = replayer.CurrentWeather(ctx, "Atlantis")
+	require.ErrorIs(t, err, provider.ErrLocationNotFound)
+	_, err = replayer.Forecast(ctx, "Kyiv", 3)
+	require.ErrorIs(t, err, provider.ErrLocationNotFound)
+	require.Equal(t, 2, calls)
+}
+
+func TestReplayDefaultFixtures(t *testing.T) {
+	wp := NewWeatherAPIProvider(testBaseURL, "", &http.Client{Transport: &ReplayTransport{Dir: "../../../fixtures/weatherapi"}})
+	ctx := context.Background()
+
+	weather, err := wp.CurrentWeather(ctx, "Anywhere")
+	require.NoError(t, err)
+	require.NotEmpty(t, weather.Description)
+
+	forecast, err := wp.Forecast(ctx, "Anywhere", 3)
+	require.NoError(t, err)
+	require.Len(t, forecast.Days, 3)
+
+	location, err := wp.ResolveLocation(ctx, "Anywhere")
+	require.NoError(t, err)
+	require.Equal(t, "Europe/Kyiv", location.Timezone)
+}
@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"Weather-API-Application/internal/model"
+	"Weather-API-Application/internal/provider"
+	"Weather-API-Application/internal/quota"
+	"Weather-API-Application/internal/resilience"
+)
+
+// ResilientProvider retries transient failures of an upstream provider with exponential
+// backoff and stops calling it while its circuit breaker is open. Rejected calls fail
+// fast with a *resilience.OpenError.
+type ResilientProvider struct {
+	wp      provider.WeatherProvider
+	policy  resilience.Policy
+	breaker *resilience.Breaker
+}
+
+func NewResilientProvider(wp provider.WeatherProvider, policy resilience.Policy, breaker *resilience.Breaker) provider.WeatherProvider {
+	return &ResilientProvider{
+		wp:      wp,
+		policy:  policy,
+		breaker: breaker,
+	}
+}
+
+func (p *ResilientProvider) Name() string {
+	return p.wp.Name()
+}
+
+func (p *ResilientProvider) CurrentWeather(ctx context.Context, location string) (*model.Weather, error) {
+	return resilientCall(ctx, p, func(ctx context.Context) (*model.Weather, error) {
+		return p.wp.CurrentWeather(ctx, location)
+	})
+}
+
+func (p *ResilientProvider) Forecast(ctx context.Context, location string, days int) (*model.Forecast, error) {
+	return resilientCall(ctx, p, func(ctx context.Context) (*model.Forecast, error) {
+		return p.wp.Forecast(ctx, location, days)
+	})
+}
+
+func (p *ResilientProvider) HourlyForecast(ctx context.Context, location string, from, to time.Time) (*model.HourlyForecast, error) {
+	return resilientCall(ctx, p, func(ctx context.Context) (*model.HourlyForecast, error) {
+		return p.wp.HourlyForecast(ctx, location, from, to)
+	})
+}
+
+func (p *ResilientProvider) History(ctx context.Context, location string, date time.Time) (*model.History, error) {
+	return resilientCall(ctx, p, func(ctx context.Context) (*model.History, error) {
+		return p.wp.History(ctx, location, date)
+	})
+}
+
+func (p *ResilientProvider) AirQuality(ctx context.Context, location string) (*model.AirQuality, error) {
+	return resilientCall(ctx, p, func(ctx context.Context) (*model.AirQuality, error) {
+		return p.wp.AirQuality(ctx, location)
+	})
+}
+
+func (p *ResilientProvider) Alerts(ctx context.Context, location string) (*model.Alerts, error) {
+	return resilientCall(ctx, p, func(ctx context.Context) (*model.Alerts, error) {
+		return p.wp.Alerts(ctx, location)
+	})
+}
+
+func (p *ResilientProvider) Astronomy(ctx context.Context, location string, date time.Time) (*model.Astronomy, error) {
+	return resilientCall(ctx, p, func(ctx context.Context) (*model.Astronomy, error) {
+		return p.wp.Astronomy(ctx, location, date)
+	})
+}
+
+func (p *ResilientProvider) SearchLocations(ctx context.Context, query string) ([]model.Location, error) {
+	return resilientCall(ctx, p, func(ctx context.Context) ([]model.Location, error) {
+		return p.wp.SearchLocations(ctx, query)
+	})
+}
+
+func (p *ResilientProvider) ResolveLocation(ctx context.Context, query string) (*model.Location, error) {
+	return resilientCall(ctx, p, func(ctx context.Context) (*model.Location, error) {
+		return p.wp.ResolveLocation(ctx, query)
+	})
+}
+
+// resilientCall runs fn with retries inside the provider's circuit breaker, so one call
+// counts once towards the breaker however many attempts it took.
+func resilientCall[T any](ctx context.Context, p *ResilientProvider, fn func(context.Context) (T, error)) (T, error) {
+	var res T
+	err := p.breaker.Do(ctx, func(ctx context.Context) error {
+		return resilience.Retry(ctx, p.policy, func(ctx context.Context) error {
+			var err error
+			res, err = fn(ctx)
+			return err
+		}, isRetryable)
+	}, isBreakerFailure)
+	return res, err
+}
+
+// isBreakerFailure reports whether err counts towards opening the circuit breaker.
+// Calls refused by the local budget meter never reached the upstream, so they do not.
+func isBreakerFailure(err error) bool {
+	return provider.IsTransient(err) && !errors.Is(err, quota.ErrBudgetExhausted)
+}
+
+// isRetryable reports whether another attempt may succeed. Exceeded quotas are
+// transient but not retried, as they do not recover within a request.
+func isRetryable(err error) bool {
+	return provider.IsTransient(err) && !errors.Is(err, provider.ErrQuotaExceeded)
+}
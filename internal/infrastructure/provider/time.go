@@ -0,0 +1,23 @@
+package provider
+
+import (
+	"math"
+	"time"
+)
+
+// maxUpstreamForecastDays is the longest daily forecast the upstream APIs are asked for.
+const maxUpstreamForecastDays = 14
+
+// inWindow reports whether t falls between from and to, inclusive.
+func inWindow(t, from, to time.Time) bool {
+	return !t.Before(from) && !t.After(to)
+}
+
+// forecastDaysUntil returns how many forecast days starting today are needed to cover to.
+// One extra day compensates for the location's timezone being ahead of UTC.
+func forecastDaysUntil(to time.Time) int {
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(math.Ceil(to.Sub(today).Hours()/24)) + 1
+	return min(max(days, 1), maxUpstreamForecastDays)
+}
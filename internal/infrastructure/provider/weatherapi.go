@@ -0,0 +1,343 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"Weather-API-Application/internal/model"
+	"Weather-API-Application/internal/provider"
+	"Weather-API-Application/internal/utils/airquality"
+)
+
+const (
+	WeatherAPIName = "weatherapi"
+
+	// weatherAPINoLocationCode is the WeatherAPI.com error code for "No matching location found".
+	weatherAPINoLocationCode = 1006
+	// weatherAPIQuotaExceededCode is the WeatherAPI.com error code for "API key has exceeded calls per month quota".
+	weatherAPIQuotaExceededCode = 2007
+)
+
+// WeatherAPIProvider fetches weather data from WeatherAPI.com.
+type WeatherAPIProvider struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewWeatherAPIProvider creates a provider calling the WeatherAPI.com API at baseURL,
+// such as "https://api.weatherapi.com/v1".
+func NewWeatherAPIProvider(baseURL, apiKey string, httpClient *http.Client) provider.WeatherProvider {
+	return &WeatherAPIProvider{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		httpClient: httpClient,
+	}
+}
+
+func (p *WeatherAPIProvider) Name() string {
+	return WeatherAPIName
+}
+
+// CurrentWeather calls the WeatherAPI.com current.json endpoint.
+func (p *WeatherAPIProvider) CurrentWeather(ctx context.Context, location string) (*model.Weather, error) {
+	params := url.Values{}
+	params.Set("q", location)
+	params.Set("aqi", "no")
+
+	var resp model.WeatherAPIResponse
+	if err := p.get(ctx, "current.json", params, &resp); err != nil {
+		return nil, err
+	}
+
+	current := resp.Current
+	return &model.Weather{
+		Temperature:   current.TempC,
+		FeelsLike:     current.FeelsLikeC,
+		Humidity:      current.Humidity,
+		Description:   current.Condition.Text,
+		WindSpeed:     current.WindKph,
+		WindDirection: current.WindDegree,
+		WindGust:      current.GustKph,
+		Pressure:      current.PressureMb,
+		Precipitation: current.PrecipMm,
+		CloudCover:    current.Cloud,
+		Visibility:    current.VisKm,
+		UVIndex:       &current.UV,
+		ObservedAt:    time.Unix(current.LastUpdatedEpoch, 0).UTC(),
+	}, nil
+}
+
+// Forecast calls the WeatherAPI.com forecast.json endpoint.
+func (p *WeatherAPIProvider) Forecast(ctx context.Context, location string, days int) (*model.Forecast, error) {
+	params := url.Values{}
+	params.Set("q", location)
+	params.Set("days", strconv.Itoa(days))
+	params.Set("aqi", "no")
+	params.Set("alerts", "no")
+
+	var resp model.WeatherAPIForecastResponse
+	if err := p.get(ctx, "forecast.json", params, &resp); err != nil {
+		return nil, err
+	}
+
+	forecast := &model.Forecast{City: resp.Location.Name}
+	for _, fd := range resp.Forecast.ForecastDay {
+		forecast.Days = append(forecast.Days, model.ForecastDay{
+			Date:           fd.Date,
+			MinTemperature: fd.Day.MinTempC,
+			MaxTemperature: fd.Day.MaxTempC,
+			ChanceOfRain:   fd.Day.DailyChanceOfRain,
+			Condition:      fd.Day.Condition.Text,
+			MaxWindSpeed:   fd.Day.MaxWindKph,
+		})
+	}
+	return forecast, nil
+}
+
+// HourlyForecast calls the WeatherAPI.com forecast.json endpoint and keeps the hours inside the window.
+func (p *WeatherAPIProvider) HourlyForecast(ctx context.Context, location string, from, to time.Time) (*model.HourlyForecast, error) {
+	params := url.Values{}
+	params.Set("q", location)
+	params.Set("days", strconv.Itoa(forecastDaysUntil(to)))
+	params.Set("aqi", "no")
+	params.Set("alerts", "no")
+
+	var resp model.WeatherAPIForecastResponse
+	if err := p.get(ctx, "forecast.json", params, &resp); err != nil {
+		return nil, err
+	}
+
+	forecast := &model.HourlyForecast{City: resp.Location.Name, Hours: []model.ForecastHour{}}
+	for _, fd := range resp.Forecast.ForecastDay {
+		for _, h := range fd.Hour {
+			t := time.Unix(h.TimeEpoch, 0).UTC()
+			if !inWindow(t, from, to) {
+				continue
+			}
+			forecast.Hours = append(forecast.Hours, model.ForecastHour{
+				Time:          t,
+				Temperature:   h.TempC,
+				Precipitation: h.PrecipMm,
+				ChanceOfRain:  h.ChanceOfRain,
+				Condition:     h.Condition.Text,
+			})
+		}
+	}
+	return forecast, nil
+}
+
+// History calls the WeatherAPI.com history.json endpoint.
+func (p *WeatherAPIProvider) History(ctx context.Context, location string, date time.Time) (*model.History, error) {
+	params := url.Values{}
+	params.Set("q", location)
+	params.Set("dt", date.Format(time.DateOnly))
+
+	var resp model.WeatherAPIForecastResponse
+	if err := p.get(ctx, "history.json", params, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Forecast.ForecastDay) == 0 {
+		return nil, provider.ErrNoData
+	}
+
+	fd := resp.Forecast.ForecastDay[0]
+	history := &model.History{
+		City:           resp.Location.Name,
+		Date:           fd.Date,
+		Source:         model.HistorySourceProvider,
+		MinTemperature: fd.Day.MinTempC,
+		MaxTemperature: fd.Day.MaxTempC,
+		AvgTemperature: fd.Day.AvgTempC,
+		AvgHumidity:    fd.Day.AvgHumidity,
+		Condition:      fd.Day.Condition.Text,
+		Hours:          []model.HistoryHour{},
+	}
+	for _, h := range fd.Hour {
+		history.Hours = append(history.Hours, model.HistoryHour{
+			Time:        time.Unix(h.TimeEpoch, 0).UTC(),
+			Temperature: h.TempC,
+			Humidity:    h.Humidity,
+			Condition:   h.Condition.Text,
+		})
+	}
+	return history, nil
+}
+
+// AirQuality calls the WeatherAPI.com current.json endpoint with air quality data enabled.
+func (p *WeatherAPIProvider) AirQuality(ctx context.Context, location string) (*model.AirQuality, error) {
+	params := url.Values{}
+	params.Set("q", location)
+	params.Set("aqi", "yes")
+
+	var resp model.WeatherAPIResponse
+	if err := p.get(ctx, "current.json", params, &resp); err != nil {
+		return nil, err
+	}
+
+	aq := resp.Current.AirQuality
+	airQuality := &model.AirQuality{
+		PM25:         aq.PM25,
+		PM10:         aq.PM10,
+		O3:           aq.O3,
+		NO2:          aq.NO2,
+		USEPAIndex:   aq.USEPAIndex,
+		GBDEFRAIndex: aq.GBDEFRAIndex,
+	}
+	airquality.Complete(airQuality)
+	return airQuality, nil
+}
+
+// Alerts calls the WeatherAPI.com forecast.json endpoint with alerts enabled.
+// WeatherAPI.com does not assign alert IDs, so they are derived from the alert content.
+func (p *WeatherAPIProvider) Alerts(ctx context.Context, location string) (*model.Alerts, error) {
+	params := url.Values{}
+	params.Set("q", location)
+	params.Set("days", "1")
+	params.Set("aqi", "no")
+	params.Set("alerts", "yes")
+
+	var resp model.WeatherAPIForecastResponse
+	if err := p.get(ctx, "forecast.json", params, &resp); err != nil {
+		return nil, err
+	}
+
+	alerts := &model.Alerts{City: resp.Location.Name, Alerts: []model.Alert{}}
+	for _, a := range resp.Alerts.Alert {
+		effective, _ := time.Parse(time.RFC3339, a.Effective)
+		expires, _ := time.Parse(time.RFC3339, a.Expires)
+		alerts.Alerts = append(alerts.Alerts, model.Alert{
+			ID:          alertID(a.Event, a.Headline, a.Areas, a.Effective),
+			Headline:    a.Headline,
+			Event:       a.Event,
+			Severity:    a.Severity,
+			Areas:       a.Areas,
+			Description: a.Desc,
+			Effective:   effective,
+			Expires:     expires,
+		})
+	}
+	return alerts, nil
+}
+
+// Astronomy calls the WeatherAPI.com astronomy.json endpoint. Times are returned in the location's timezone.
+func (p *WeatherAPIProvider) Astronomy(ctx context.Context, location string, date time.Time) (*model.Astronomy, error) {
+	params := url.Values{}
+	params.Set("q", location)
+	params.Set("dt", date.Format(time.DateOnly))
+
+	var resp model.WeatherAPIAstronomyResponse
+	if err := p.get(ctx, "astronomy.json", params, &resp); err != nil {
+		return nil, err
+	}
+
+	loc, err := time.LoadLocation(resp.Location.TzID)
+	if err != nil {
+		loc = time.UTC
+	}
+	astro := resp.Astronomy.Astro
+	return &model.Astronomy{
+		City:             resp.Location.Name,
+		Date:             date.Format(time.DateOnly),
+		Source:           model.AstronomySourceProvider,
+		Sunrise:          parseClockTime(date, astro.Sunrise, loc),
+		Sunset:           parseClockTime(date, astro.Sunset, loc),
+		Moonrise:         parseClockTime(date, astro.Moonrise, loc),
+		Moonset:          parseClockTime(date, astro.Moonset, loc),
+		MoonPhase:        astro.MoonPhase,
+		MoonIllumination: astro.MoonIllumination,
+	}, nil
+}
+
+// parseClockTime combines the date with a WeatherAPI.com "05:03 AM" time of day.
+// It returns nil for values such as "No moonrise".
+func parseClockTime(date time.Time, clock string, loc *time.Location) *time.Time {
+	t, err := time.Parse("03:04 PM", clock)
+	if err != nil {
+		return nil
+	}
+	result := time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), 0, 0, loc)
+	return &result
+}
+
+// SearchLocations calls the WeatherAPI.com search.json endpoint.
+func (p *WeatherAPIProvider) SearchLocations(ctx context.Context, query string) ([]model.Location, error) {
+	params := url.Values{}
+	params.Set("q", query)
+
+	var resp []model.WeatherAPISearchResult
+	if err := p.get(ctx, "search.json", params, &resp); err != nil {
+		return nil, err
+	}
+
+	locations := make([]model.Location, 0, len(resp))
+	for _, r := range resp {
+		locations = append(locations, model.Location{
+			ID:      WeatherAPIName + ":" + strconv.FormatInt(r.ID, 10),
+			Name:    r.Name,
+			Region:  r.Region,
+			Country: r.Country,
+			Lat:     r.Lat,
+			Lon:     r.Lon,
+		})
+	}
+	return limitLocations(locations), nil
+}
+
+// ResolveLocation takes the best match of the WeatherAPI.com search.json endpoint
+// and looks up its timezone through the timezone.json endpoint.
+func (p *WeatherAPIProvider) ResolveLocation(ctx context.Context, query string) (*model.Location, error) {
+	locations, err := p.SearchLocations(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(locations) == 0 {
+		return nil, provider.ErrLocationNotFound
+	}
+	location := locations[0]
+
+	params := url.Values{}
+	params.Set("q", "id:"+strings.TrimPrefix(location.ID, WeatherAPIName+":"))
+
+	var resp model.WeatherAPITimezoneResponse
+	if err := p.get(ctx, "timezone.json", params, &resp); err != nil {
+		return nil, err
+	}
+	location.Timezone = resp.Location.TzID
+	return &location, nil
+}
+
+// alertID derives a stable identifier from the fields that identify an alert.
+func alertID(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "|")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// get calls the given WeatherAPI.com endpoint and maps error responses to provider errors.
+func (p *WeatherAPIProvider) get(ctx context.Context, endpoint string, params url.Values, dst any) error {
+	params.Set("key", p.apiKey)
+	rawURL := p.baseURL + "/" + endpoint + "?" + params.Encode()
+
+	var errResp model.WeatherAPIErrorResponse
+	status, err := fetchJSON(ctx, p.httpClient, rawURL, dst, &errResp)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		switch errResp.Error.Code {
+		case weatherAPINoLocationCode:
+			return provider.ErrLocationNotFound
+		case weatherAPIQuotaExceededCode:
+			return fmt.Errorf("%s: %w", WeatherAPIName, provider.ErrQuotaExceeded)
+		}
+		return &provider.UpstreamError{Provider: WeatherAPIName, StatusCode: status}
+	}
+	return nil
+}
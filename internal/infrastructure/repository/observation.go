@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"Weather-API-Application/internal/model"
+	"Weather-API-Application/internal/repository"
+	"context"
+	"database/sql"
+	"time"
+)
+
+type ObservationRepository struct {
+	db *sql.DB
+}
+
+func NewObservationRepository(db *sql.DB) repository.ObservationRepository {
+	return &ObservationRepository{db: db}
+}
+
+func (r *ObservationRepository) Save(ctx context.Context, o *model.Observation) error {
+	const query = `
+		INSERT INTO weather_observations (city, observed_at, temperature, humidity, description)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := r.db.ExecContext(ctx, query, o.City, o.ObservedAt, o.Temperature, o.Humidity, o.Description)
+	return err
+}
+
+// ListByCity returns observations for the city with from <= observed_at < to, oldest first.
+func (r *ObservationRepository) ListByCity(ctx context.Context, city string, from, to time.Time) ([]*model.Observation, error) {
+	const query = `
+		SELECT city, observed_at, temperature, humidity, description
+		FROM weather_observations
+		WHERE city = $1 AND observed_at >= $2 AND observed_at < $3
+		ORDER BY observed_at
+	`
+	rows, err := r.db.QueryContext(ctx, query, city, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var observations []*model.Observation
+	for rows.Next() {
+		o := new(model.Observation)
+		if err := rows.Scan(&o.City, &o.ObservedAt, &o.Temperature, &o.Humidity, &o.Description); err != nil {
+			return nil, err
+		}
+		observations = append(observations, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return observations, nil
+}
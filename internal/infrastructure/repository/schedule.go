@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"Weather-API-Application/internal/model"
+	"Weather-API-Application/internal/repository"
+	"context"
+	"database/sql"
+	"time"
+)
+
+// maxDeliveryAttempts is how many times a slot is claimed before a send interrupted by a crash is given up.
+const maxDeliveryAttempts = 3
+
+// SetNextRun schedules the subscription at nextRun, or unschedules it if nextRun is nil.
+// Missing subscriptions are ignored.
+func (r *SubscriptionRepository) SetNextRun(ctx context.Context, subId string, nextRun *time.Time) error {
+	const query = `
+		UPDATE weather_subscriptions
+		SET next_run_at = $2
+		WHERE id = $1
+	`
+	_, err := r.db.ExecContext(ctx, query, subId, nextRun)
+	return err
+}
+
+// ClaimDue claims up to limit runs due at now in one transaction:
+//   - pending or sending deliveries claimed, or started sending, more than lease ago, left by a
+//     scheduler that stopped before completing them, are claimed again as pending until they
+//     reach maxDeliveryAttempts;
+//   - confirmed subscriptions with next_run_at <= now are locked with FOR UPDATE SKIP LOCKED, so
+//     concurrent schedulers claim disjoint rows, and moved to next(sub, next_run_at). Updates also
+//     get a pending delivery for the claimed slot; alert checks are de-duplicated per alert instead.
+func (r *SubscriptionRepository) ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration, next repository.NextRunFunc) ([]*model.Delivery, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	deliveries, err := claimStaleDeliveries(ctx, tx, now, limit, lease)
+	if err != nil {
+		return nil, err
+	}
+	if remaining := limit - len(deliveries); remaining > 0 {
+		due, err := claimDueSubscriptions(ctx, tx, now, remaining, next)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, due...)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func claimStaleDeliveries(ctx context.Context, tx *sql.Tx, now time.Time, limit int, lease time.Duration) ([]*model.Delivery, error) {
+	const query = `
+		WITH stale AS (
+			SELECT subscription_id, slot
+			FROM subscription_deliveries
+			WHERE status IN ('pending', 'sending') AND claimed_at <= $1 AND attempts < $2
+			ORDER BY slot
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		), claimed AS (
+			UPDATE subscription_deliveries d
+			SET status = 'pending', claimed_at = $4, attempts = d.attempts + 1
+			FROM stale
+			WHERE d.subscription_id = stale.subscription_id AND d.slot = stale.slot
+			RETURNING d.subscription_id, d.slot, d.attempts
+		)
+		SELECT ` + subscriptionColumns + `, claimed.slot, claimed.attempts
+		FROM claimed
+		JOIN weather_subscriptions ON weather_subscriptions.id = claimed.subscription_id
+		WHERE confirmed = TRUE
+	`
+	rows, err := tx.QueryContext(ctx, query, now.Add(-lease), maxDeliveryAttempts, limit, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*model.Delivery
+	for rows.Next() {
+		d := &model.Delivery{}
+		d.Subscription, err = scanSubscription(rows, &d.Slot, &d.Attempts)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+func claimDueSubscriptions(ctx context.Context, tx *sql.Tx, now time.Time, limit int, next repository.NextRunFunc) ([]*model.Delivery, error) {
+	const query = `
+		SELECT ` + subscriptionColumns + `
+		FROM weather_subscriptions
+		WHERE confirmed = TRUE AND next_run_at <= $1
+		ORDER BY next_run_at
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	`
+	subs, err := listSubscriptions(ctx, tx, query, now, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	const reschedule = `
+		UPDATE weather_subscriptions
+		SET next_run_at = $2
+		WHERE id = $1
+	`
+	const insertDelivery = `
+		INSERT INTO subscription_deliveries (subscription_id, slot, claimed_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (subscription_id, slot) DO NOTHING
+	`
+	deliveries := make([]*model.Delivery, 0, len(subs))
+	for _, sub := range subs {
+		slot := *sub.NextRunAt
+		nextRun := next(sub, slot)
+		if _, err := tx.ExecContext(ctx, reschedule, sub.ID, nextRun); err != nil {
+			return nil, err
+		}
+		sub.NextRunAt = &nextRun
+
+		if sub.Frequency != model.FrequencyAlerts {
+			res, err := tx.ExecContext(ctx, insertDelivery, sub.ID, slot, now)
+			if err != nil {
+				return nil, err
+			}
+			if aff, _ := res.RowsAffected(); aff == 0 {
+				// The slot was already claimed
+				continue
+			}
+		}
+		deliveries = append(deliveries, &model.Delivery{Subscription: sub, Slot: slot, Attempts: 1})
+	}
+	return deliveries, nil
+}
+
+// StartDelivery marks a pending delivery as sending right before its email is sent, and restarts
+// its lease at now. It returns false if the delivery is no longer pending, because another claim of
+// the slot already started sending it, or it was completed or given up.
+func (r *SubscriptionRepository) StartDelivery(ctx context.Context, subId string, slot time.Time, now time.Time) (bool, error) {
+	const query = `
+		UPDATE subscription_deliveries
+		SET status = 'sending', claimed_at = $3
+		WHERE subscription_id = $1 AND slot = $2 AND status = 'pending'
+	`
+	res, err := r.db.ExecContext(ctx, query, subId, slot, now)
+	if err != nil {
+		return false, err
+	}
+	aff, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return aff == 1, nil
+}
+
+// CompleteDelivery records the outcome of a claimed update and, when it was sent, the
+// subscription's last_sent_at. Slots without a pending or sending delivery, such as alert checks
+// and deliveries given up in the meantime, are ignored.
+func (r *SubscriptionRepository) CompleteDelivery(ctx context.Context, subId string, slot time.Time, sendErr error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	status, errText := model.DeliverySent, sql.NullString{}
+	if sendErr != nil {
+		status, errText = model.DeliveryFailed, sql.NullString{String: sendErr.Error(), Valid: true}
+	}
+
+	const complete = `
+		UPDATE subscription_deliveries
+		SET status = $3, error = $4, sent_at = CASE WHEN $3 = 'sent' THEN NOW() END
+		WHERE subscription_id = $1 AND slot = $2 AND status IN ('pending', 'sending')
+	`
+	res, err := tx.ExecContext(ctx, complete, subId, slot, status, errText)
+	if err != nil {
+		return err
+	}
+	if aff, _ := res.RowsAffected(); aff == 1 && sendErr == nil {
+		const lastSent = `
+			UPDATE weather_subscriptions
+			SET last_sent_at = NOW()
+			WHERE id = $1
+		`
+		if _, err := tx.ExecContext(ctx, lastSent, subId); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ListUnscheduled returns confirmed subscriptions without a next run, such as those confirmed
+// while scheduling them failed.
+func (r *SubscriptionRepository) ListUnscheduled(ctx context.Context) ([]*model.Subscription, error) {
+	const query = `
+		SELECT ` + subscriptionColumns + `
+		FROM weather_subscriptions
+		WHERE confirmed = TRUE AND next_run_at IS NULL
+		ORDER BY id
+	`
+	return r.list(ctx, query)
+}
+
+// ClearUnconfirmedSchedules unschedules subscriptions that are no longer confirmed.
+func (r *SubscriptionRepository) ClearUnconfirmedSchedules(ctx context.Context) (int64, error) {
+	const query = `
+		UPDATE weather_subscriptions
+		SET next_run_at = NULL
+		WHERE confirmed = FALSE AND next_run_at IS NOT NULL
+	`
+	res, err := r.db.ExecContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// FailAbandonedDeliveries marks pending or sending deliveries that were claimed maxDeliveryAttempts
+// times, the last one before claimedBefore, as failed.
+func (r *SubscriptionRepository) FailAbandonedDeliveries(ctx context.Context, claimedBefore time.Time) (int64, error) {
+	const query = `
+		UPDATE subscription_deliveries
+		SET status = 'failed', error = 'abandoned after ' || attempts || ' interrupted attempts'
+		WHERE status IN ('pending', 'sending') AND attempts >= $1 AND claimed_at <= $2
+	`
+	res, err := r.db.ExecContext(ctx, query, maxDeliveryAttempts, claimedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -6,13 +6,24 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 type SubscriptionRepository struct {
 	db *sql.DB
 }
 
-var ErrNotFound = errors.New("subscription not found")
+// uniqueViolationCode is the Postgres error code for unique constraint violations.
+const uniqueViolationCode = "23505"
+
+// subscriptionColumns are the columns read by scanSubscription, in order.
+const subscriptionColumns = `id, email, city, latitude, longitude, location_id, location_name, location_country, location_timezone,
+	frequency, include_air_quality, units, language, token, confirmed, next_run_at, last_sent_at`
+
+// matchSubscription selects the subscription of email $1 for the same canonical location ($2)
+// or, for rows not resolved yet, the same city ($3, case-insensitive).
+const matchSubscription = `email = $1 AND (location_id = $2 OR (location_id IS NULL AND lower(city) = lower($3)))`
 
 func NewSubscriptionRepository(db *sql.DB) repository.SubscriptionRepository {
 	return &SubscriptionRepository{db: db}
@@ -22,9 +33,11 @@ func (r *SubscriptionRepository) CheckConfirmation(ctx context.Context, subscrip
 	const query = `
 		SELECT confirmed
 		FROM weather_subscriptions
-		WHERE email = $1 AND city = $2
+		WHERE ` + matchSubscription + `
+		ORDER BY confirmed DESC
+		LIMIT 1
 	`
-	row := r.db.QueryRowContext(ctx, query, subscriptionRequest.Email, subscriptionRequest.City)
+	row := r.db.QueryRowContext(ctx, query, subscriptionRequest.Email, locationID(subscriptionRequest), subscriptionRequest.City)
 	err = row.Scan(&confirmed)
 
 	if errors.Is(err, sql.ErrNoRows) {
@@ -38,59 +51,56 @@ func (r *SubscriptionRepository) CheckConfirmation(ctx context.Context, subscrip
 
 func (r *SubscriptionRepository) Create(ctx context.Context, s *model.Subscription) error {
 	const query = `
-		INSERT INTO weather_subscriptions (email, city, token, frequency, confirmed, created_at)
-		VALUES ($1,   $2,   $3,    $4,       FALSE,     NOW())
+		INSERT INTO weather_subscriptions (email, city, latitude, longitude, location_id, location_name, location_country, location_timezone,
+		                                   token, frequency, include_air_quality, units, language, confirmed, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, FALSE, NOW())
 	`
-	_, err := r.db.ExecContext(ctx, query, s.Email, s.City, s.Token, s.Frequency)
+	var name, country, timezone sql.NullString
+	if s.Location != nil {
+		name = sql.NullString{String: s.Location.Name, Valid: true}
+		country = sql.NullString{String: s.Location.Country, Valid: true}
+		timezone = sql.NullString{String: s.Location.Timezone, Valid: s.Location.Timezone != ""}
+	}
+	_, err := r.db.ExecContext(ctx, query, s.Email, s.City, s.Latitude, s.Longitude, locationID(s), name, country, timezone,
+		s.Token, s.Frequency, s.IncludeAirQuality, s.Units, s.Language)
+	if isUniqueViolation(err) {
+		return repository.ErrDuplicate
+	}
 	return err
 }
 
 func (r *SubscriptionRepository) UpdateTokenByEmailCity(ctx context.Context, s *model.Subscription) error {
 	const query = `
 		UPDATE weather_subscriptions
-		SET token = $1, confirmed = FALSE, created_at = NOW()
-		WHERE email = $2 AND city = $3
+		SET token = $4, confirmed = FALSE, created_at = NOW()
+		WHERE ` + matchSubscription + `
 	`
-	res, err := r.db.ExecContext(ctx, query, s.Token, s.Email, s.City)
+	res, err := r.db.ExecContext(ctx, query, s.Email, locationID(s), s.City, s.Token)
 	if err != nil {
 		return err
 	}
 	aff, _ := res.RowsAffected()
 	if aff == 0 {
 		// No rows affected - return domain error
-		return ErrNotFound
+		return repository.ErrNotFound
 	}
 	return nil
 }
 
 func (r *SubscriptionRepository) GetByToken(ctx context.Context, token string) (string, *model.Subscription, error) {
 	const query = `
-		SELECT id, email, city, frequency, confirmed
+		SELECT ` + subscriptionColumns + `
 		FROM weather_subscriptions
 		WHERE token = $1
 	`
-	var (
-		id        string
-		email     string
-		city      string
-		frequency string
-		confirmed bool
-	)
-	err := r.db.QueryRowContext(ctx, query, token).Scan(&id, &email, &city, &frequency, &confirmed)
+	sub, err := scanSubscription(r.db.QueryRowContext(ctx, query, token))
 	if errors.Is(err, sql.ErrNoRows) {
-		return "", nil, ErrNotFound
+		return "", nil, repository.ErrNotFound
 	}
 	if err != nil {
 		return "", nil, err
 	}
-
-	return id, &model.Subscription{
-		Email:     email,
-		City:      city,
-		Frequency: frequency,
-		Token:     token,
-		Confirmed: confirmed,
-	}, nil
+	return sub.ID, sub, nil
 }
 
 func (r *SubscriptionRepository) SetConfirmed(ctx context.Context, subId string) error {
@@ -105,7 +115,7 @@ func (r *SubscriptionRepository) SetConfirmed(ctx context.Context, subId string)
 	}
 	aff, _ := res.RowsAffected()
 	if aff == 0 {
-		return ErrNotFound
+		return repository.ErrNotFound
 	}
 	return nil
 }
@@ -121,19 +131,111 @@ func (r *SubscriptionRepository) DeleteByToken(ctx context.Context, token string
 	}
 	aff, _ := res.RowsAffected()
 	if aff == 0 {
-		return ErrNotFound
+		return repository.ErrNotFound
 	}
 	return nil
 }
 
 func (r *SubscriptionRepository) ListConfirmed(ctx context.Context) ([]*model.Subscription, error) {
 	const query = `
-		SELECT email, city, frequency, token, confirmed
+		SELECT ` + subscriptionColumns + `
 		FROM weather_subscriptions
 		WHERE confirmed = TRUE
 		ORDER BY email, city
 	`
-	rows, err := r.db.QueryContext(ctx, query)
+	return r.list(ctx, query)
+}
+
+// ListUnresolved returns up to limit subscriptions without a canonical location that failed
+// to resolve fewer than maxAttempts times, confirmed ones first.
+func (r *SubscriptionRepository) ListUnresolved(ctx context.Context, maxAttempts, limit int) ([]*model.Subscription, error) {
+	const query = `
+		SELECT ` + subscriptionColumns + `
+		FROM weather_subscriptions
+		WHERE location_id IS NULL AND location_attempts < $1
+		ORDER BY confirmed DESC, id
+		LIMIT $2
+	`
+	return r.list(ctx, query, maxAttempts, limit)
+}
+
+// RecordLocationFailure counts a failed attempt to resolve the location of a subscription.
+func (r *SubscriptionRepository) RecordLocationFailure(ctx context.Context, subId string) error {
+	const query = `
+		UPDATE weather_subscriptions
+		SET location_attempts = location_attempts + 1
+		WHERE id = $1
+	`
+	res, err := r.db.ExecContext(ctx, query, subId)
+	if err != nil {
+		return err
+	}
+	aff, _ := res.RowsAffected()
+	if aff == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// SetLocation stores the canonical location of a subscription, and its coordinates unless the
+// subscriber gave their own. It returns repository.ErrDuplicate if the email is already subscribed
+// to that location.
+func (r *SubscriptionRepository) SetLocation(ctx context.Context, subId string, location *model.Location) error {
+	const query = `
+		UPDATE weather_subscriptions
+		SET location_id = $1, location_name = $2, location_country = $3, location_timezone = $4,
+			latitude = CASE WHEN latitude IS NULL OR longitude IS NULL THEN $5 ELSE latitude END,
+			longitude = CASE WHEN latitude IS NULL OR longitude IS NULL THEN $6 ELSE longitude END
+		WHERE id = $7
+	`
+	timezone := sql.NullString{String: location.Timezone, Valid: location.Timezone != ""}
+	res, err := r.db.ExecContext(ctx, query, location.ID, location.Name, location.Country, timezone, location.Lat, location.Lon, subId)
+	if isUniqueViolation(err) {
+		return repository.ErrDuplicate
+	}
+	if err != nil {
+		return err
+	}
+	aff, _ := res.RowsAffected()
+	if aff == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// MarkAlertSent records that the alert was sent to the subscription.
+// It returns false if the alert had already been recorded.
+func (r *SubscriptionRepository) MarkAlertSent(ctx context.Context, subId string, alertId string) (bool, error) {
+	const query = `
+		INSERT INTO sent_alerts (subscription_id, alert_id, sent_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (subscription_id, alert_id) DO NOTHING
+	`
+	res, err := r.db.ExecContext(ctx, query, subId, alertId)
+	if err != nil {
+		return false, err
+	}
+	aff, _ := res.RowsAffected()
+	return aff == 1, nil
+}
+
+// UnmarkAlertSent removes the sent record so the alert is retried on the next poll.
+func (r *SubscriptionRepository) UnmarkAlertSent(ctx context.Context, subId string, alertId string) error {
+	const query = `
+		DELETE FROM sent_alerts
+		WHERE subscription_id = $1 AND alert_id = $2
+	`
+	_, err := r.db.ExecContext(ctx, query, subId, alertId)
+	return err
+}
+
+func (r *SubscriptionRepository) list(ctx context.Context, query string, args ...any) ([]*model.Subscription, error) {
+	return listSubscriptions(ctx, r.db, query, args...)
+}
+
+// listSubscriptions runs a query selecting subscriptionColumns and reads all rows.
+func listSubscriptions(ctx context.Context, q querier, query string, args ...any) ([]*model.Subscription, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -141,8 +243,8 @@ func (r *SubscriptionRepository) ListConfirmed(ctx context.Context) ([]*model.Su
 
 	var subs []*model.Subscription
 	for rows.Next() {
-		s := new(model.Subscription)
-		if err := rows.Scan(&s.Email, &s.City, &s.Frequency, &s.Token, &s.Confirmed); err != nil {
+		s, err := scanSubscription(rows)
+		if err != nil {
 			return nil, err
 		}
 		subs = append(subs, s)
@@ -152,3 +254,47 @@ func (r *SubscriptionRepository) ListConfirmed(ctx context.Context) ([]*model.Su
 	}
 	return subs, nil
 }
+
+// querier is implemented by *sql.DB and *sql.Tx.
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// scanSubscription reads a row selected with subscriptionColumns, followed by the extra columns if any.
+func scanSubscription(row interface{ Scan(dest ...any) error }, extra ...any) (*model.Subscription, error) {
+	var (
+		s                           model.Subscription
+		id, name, country, timezone sql.NullString
+	)
+	dest := []any{&s.ID, &s.Email, &s.City, &s.Latitude, &s.Longitude, &id, &name, &country, &timezone,
+		&s.Frequency, &s.IncludeAirQuality, &s.Units, &s.Language, &s.Token, &s.Confirmed, &s.NextRunAt, &s.LastSentAt}
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+	if id.Valid {
+		s.Location = &model.Location{
+			ID:       id.String,
+			Name:     name.String,
+			Country:  country.String,
+			Timezone: timezone.String,
+		}
+		if s.Latitude != nil && s.Longitude != nil {
+			s.Location.Lat, s.Location.Lon = *s.Latitude, *s.Longitude
+		}
+	}
+	return &s, nil
+}
+
+// locationID returns the canonical location ID of the subscription, or NULL if it is not resolved.
+func locationID(s *model.Subscription) sql.NullString {
+	if s.Location == nil || s.Location.ID == "" {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: s.Location.ID, Valid: true}
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode
+}
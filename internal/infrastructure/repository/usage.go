@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"Weather-API-Application/internal/model"
+	"Weather-API-Application/internal/repository"
+	"context"
+	"database/sql"
+)
+
+type UsageRepository struct {
+	db *sql.DB
+}
+
+func NewUsageRepository(db *sql.DB) repository.UsageRepository {
+	return &UsageRepository{db: db}
+}
+
+// Add counts calls in both the day and the month, returning the updated totals.
+func (r *UsageRepository) Add(ctx context.Context, provider, day, month string, calls int64) (model.Usage, error) {
+	const query = `
+		INSERT INTO provider_usage (provider, period, calls, updated_at)
+		VALUES ($1, $2, $4, NOW()), ($1, $3, $4, NOW())
+		ON CONFLICT (provider, period) DO UPDATE
+		SET calls = provider_usage.calls + EXCLUDED.calls, updated_at = NOW()
+		RETURNING period, calls
+	`
+	return r.usage(ctx, query, provider, day, month, calls)
+}
+
+func (r *UsageRepository) Get(ctx context.Context, provider, day, month string) (model.Usage, error) {
+	const query = `
+		SELECT period, calls
+		FROM provider_usage
+		WHERE provider = $1 AND period IN ($2, $3)
+	`
+	return r.usage(ctx, query, provider, day, month)
+}
+
+// usage runs a query returning (period, calls) rows for the day and month.
+func (r *UsageRepository) usage(ctx context.Context, query, provider, day, month string, args ...any) (model.Usage, error) {
+	rows, err := r.db.QueryContext(ctx, query, append([]any{provider, day, month}, args...)...)
+	if err != nil {
+		return model.Usage{}, err
+	}
+	defer rows.Close()
+
+	var usage model.Usage
+	for rows.Next() {
+		var (
+			period string
+			calls  int64
+		)
+		if err := rows.Scan(&period, &calls); err != nil {
+			return model.Usage{}, err
+		}
+		switch period {
+		case day:
+			usage.Daily = calls
+		case month:
+			usage.Monthly = calls
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return model.Usage{}, err
+	}
+	return usage, nil
+}
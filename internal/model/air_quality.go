@@ -0,0 +1,33 @@
+package model
+
+// AirQuality holds pollutant concentrations in µg/m³ and the derived air quality indices.
+type AirQuality struct {
+	PM25            float64 `json:"pm2_5"`
+	PM10            float64 `json:"pm10"`
+	O3              float64 `json:"o3"`
+	NO2             float64 `json:"no2"`
+	USEPAIndex      int     `json:"us_epa_index" example:"2"`
+	USEPACategory   string  `json:"us_epa_category" example:"Moderate"`
+	GBDEFRAIndex    int     `json:"gb_defra_index" example:"3"`
+	GBDEFRACategory string  `json:"gb_defra_category" example:"Low"`
+}
+
+type OpenMeteoAirQualityResponse struct {
+	Current struct {
+		PM25            float64 `json:"pm2_5"`
+		PM10            float64 `json:"pm10"`
+		Ozone           float64 `json:"ozone"`
+		NitrogenDioxide float64 `json:"nitrogen_dioxide"`
+	} `json:"current"`
+}
+
+type OpenWeatherMapAirPollutionResponse struct {
+	List []struct {
+		Components struct {
+			PM25 float64 `json:"pm2_5"`
+			PM10 float64 `json:"pm10"`
+			O3   float64 `json:"o3"`
+			NO2  float64 `json:"no2"`
+		} `json:"components"`
+	} `json:"list"`
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+type Alerts struct {
+	City   string  `json:"city"`
+	Alerts []Alert `json:"alerts"`
+}
+
+// Alert is a government-issued severe weather alert.
+type Alert struct {
+	ID          string    `json:"id"`
+	Headline    string    `json:"headline"`
+	Event       string    `json:"event"`
+	Severity    string    `json:"severity" example:"Moderate"`
+	Areas       string    `json:"areas"`
+	Description string    `json:"description"`
+	Effective   time.Time `json:"effective"`
+	Expires     time.Time `json:"expires"`
+}
+
+type WeatherAPIAlert struct {
+	Headline  string `json:"headline"`
+	Severity  string `json:"severity"`
+	Areas     string `json:"areas"`
+	Event     string `json:"event"`
+	Effective string `json:"effective"`
+	Expires   string `json:"expires"`
+	Desc      string `json:"desc"`
+}
@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+const (
+	AstronomySourceProvider   = "provider"
+	AstronomySourceCalculated = "calculated"
+)
+
+// Astronomy holds sun and moon data for a day. Rise and set times are null when the
+// event does not happen on that day or is unknown.
+type Astronomy struct {
+	City             string     `json:"city"`
+	Date             string     `json:"date" example:"2025-06-01"`
+	Source           string     `json:"source" enums:"provider,calculated"`
+	Sunrise          *time.Time `json:"sunrise"`
+	Sunset           *time.Time `json:"sunset"`
+	Moonrise         *time.Time `json:"moonrise"`
+	Moonset          *time.Time `json:"moonset"`
+	MoonPhase        string     `json:"moon_phase" example:"Waxing Crescent"`
+	MoonIllumination float64    `json:"moon_illumination"`
+}
+
+type WeatherAPIAstronomyResponse struct {
+	Location struct {
+		Name string `json:"name"`
+		TzID string `json:"tz_id"`
+	} `json:"location"`
+	Astronomy struct {
+		Astro struct {
+			Sunrise          string  `json:"sunrise"`
+			Sunset           string  `json:"sunset"`
+			Moonrise         string  `json:"moonrise"`
+			Moonset          string  `json:"moonset"`
+			MoonPhase        string  `json:"moon_phase"`
+			MoonIllumination float64 `json:"moon_illumination"`
+		} `json:"astro"`
+	} `json:"astronomy"`
+}
@@ -0,0 +1,25 @@
+package model
+
+type BatchWeatherRequest struct {
+	Locations []BatchLocation `json:"locations"`
+}
+
+// BatchLocation selects a location by city name or by coordinates.
+type BatchLocation struct {
+	City string   `json:"city,omitempty" example:"Kyiv"`
+	Lat  *float64 `json:"lat,omitempty"`
+	Lon  *float64 `json:"lon,omitempty"`
+}
+
+type BatchWeatherResponse struct {
+	Results []BatchWeatherResult `json:"results"`
+}
+
+// BatchWeatherResult is the outcome for one requested location, in request order.
+// Status is the HTTP status the location would get from GET /api/weather.
+type BatchWeatherResult struct {
+	Location string   `json:"location" example:"Kyiv"`
+	Status   int      `json:"status" example:"200"`
+	Weather  *Weather `json:"weather,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+const (
+	DeliveryPending = "pending"
+	DeliverySending = "sending"
+	DeliverySent    = "sent"
+	DeliveryFailed  = "failed"
+)
+
+// Delivery is a claimed run of a subscription for one scheduled slot.
+// Attempts counts the claims of the slot, more than one after a worker crashed mid-send.
+type Delivery struct {
+	Subscription *Subscription
+	Slot         time.Time
+	Attempts     int
+}
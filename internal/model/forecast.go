@@ -0,0 +1,67 @@
+package model
+
+import "time"
+
+type Forecast struct {
+	City  string        `json:"city"`
+	Units *Units        `json:"units,omitempty"`
+	Days  []ForecastDay `json:"days"`
+}
+
+type ForecastDay struct {
+	Date           string  `json:"date" example:"2025-06-01"`
+	MinTemperature float64 `json:"min_temperature"`
+	MaxTemperature float64 `json:"max_temperature"`
+	ChanceOfRain   float64 `json:"chance_of_rain"`
+	Condition      string  `json:"condition"`
+	MaxWindSpeed   float64 `json:"max_wind_speed"`
+}
+
+type HourlyForecast struct {
+	City  string         `json:"city"`
+	Units *Units         `json:"units,omitempty"`
+	Hours []ForecastHour `json:"hours"`
+}
+
+type ForecastHour struct {
+	Time          time.Time `json:"time"`
+	Temperature   float64   `json:"temperature"`
+	Precipitation float64   `json:"precipitation"`
+	ChanceOfRain  float64   `json:"chance_of_rain"`
+	Condition     string    `json:"condition"`
+}
+
+type WeatherAPIForecastResponse struct {
+	Location struct {
+		Name string `json:"name"`
+	} `json:"location"`
+	Forecast struct {
+		ForecastDay []struct {
+			Date string `json:"date"`
+			Day  struct {
+				MaxTempC          float64 `json:"maxtemp_c"`
+				MinTempC          float64 `json:"mintemp_c"`
+				AvgTempC          float64 `json:"avgtemp_c"`
+				AvgHumidity       float64 `json:"avghumidity"`
+				MaxWindKph        float64 `json:"maxwind_kph"`
+				DailyChanceOfRain float64 `json:"daily_chance_of_rain"`
+				Condition         struct {
+					Text string `json:"text"`
+				} `json:"condition"`
+			} `json:"day"`
+			Hour []struct {
+				TimeEpoch    int64   `json:"time_epoch"`
+				TempC        float64 `json:"temp_c"`
+				Humidity     float64 `json:"humidity"`
+				PrecipMm     float64 `json:"precip_mm"`
+				ChanceOfRain float64 `json:"chance_of_rain"`
+				Condition    struct {
+					Text string `json:"text"`
+				} `json:"condition"`
+			} `json:"hour"`
+		} `json:"forecastday"`
+	} `json:"forecast"`
+	Alerts struct {
+		Alert []WeatherAPIAlert `json:"alert"`
+	} `json:"alerts"`
+}
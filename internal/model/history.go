@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+const (
+	HistorySourceProvider = "provider"
+	HistorySourceLocal    = "local"
+)
+
+type History struct {
+	City           string        `json:"city"`
+	Date           string        `json:"date" example:"2025-06-01"`
+	Source         string        `json:"source" enums:"provider,local"`
+	MinTemperature float64       `json:"min_temperature"`
+	MaxTemperature float64       `json:"max_temperature"`
+	AvgTemperature float64       `json:"avg_temperature"`
+	AvgHumidity    float64       `json:"avg_humidity"`
+	Condition      string        `json:"condition"`
+	Units          *Units        `json:"units,omitempty"`
+	Hours          []HistoryHour `json:"hours"`
+}
+
+type HistoryHour struct {
+	Time        time.Time `json:"time"`
+	Temperature float64   `json:"temperature"`
+	Humidity    float64   `json:"humidity"`
+	Condition   string    `json:"condition"`
+}
+
+// Observation is a locally stored snapshot of the current weather for a city.
+type Observation struct {
+	City        string
+	ObservedAt  time.Time
+	Temperature float64
+	Humidity    float64
+	Description string
+}
@@ -0,0 +1,80 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Location is a place known to the weather provider. ID is stable for the same place
+// and is prefixed with the provider name, e.g. "weatherapi:2801268".
+type Location struct {
+	ID       string  `json:"id" example:"weatherapi:2801268"`
+	Name     string  `json:"name" example:"Kyiv"`
+	Region   string  `json:"region" example:"Kyyiv"`
+	Country  string  `json:"country" example:"Ukraine"`
+	Lat      float64 `json:"lat" example:"50.43"`
+	Lon      float64 `json:"lon" example:"30.52"`
+	Timezone string  `json:"timezone,omitempty" example:"Europe/Kyiv"`
+}
+
+type WeatherAPISearchResult struct {
+	ID      int64   `json:"id"`
+	Name    string  `json:"name"`
+	Region  string  `json:"region"`
+	Country string  `json:"country"`
+	Lat     float64 `json:"lat"`
+	Lon     float64 `json:"lon"`
+}
+
+type WeatherAPITimezoneResponse struct {
+	Location struct {
+		Name    string  `json:"name"`
+		Region  string  `json:"region"`
+		Country string  `json:"country"`
+		Lat     float64 `json:"lat"`
+		Lon     float64 `json:"lon"`
+		TzID    string  `json:"tz_id"`
+	} `json:"location"`
+}
+
+// Coordinates is a geographic position in decimal degrees.
+type Coordinates struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// coordinatePrecision is the number of decimal places kept in location queries (about 11 m),
+// so that nearby GPS fixes share cache entries and subscriptions.
+const coordinatePrecision = 4
+
+// String formats the coordinates as a "lat,lon" location query understood by the providers.
+func (c Coordinates) String() string {
+	return fmt.Sprintf("%s,%s",
+		strconv.FormatFloat(roundCoordinate(c.Lat), 'f', -1, 64),
+		strconv.FormatFloat(roundCoordinate(c.Lon), 'f', -1, 64))
+}
+
+func roundCoordinate(v float64) float64 {
+	scale := math.Pow10(coordinatePrecision)
+	return math.Round(v*scale) / scale
+}
+
+// ParseCoordinates parses a "lat,lon" location query. It returns false if the query
+// is not a pair of numbers within the valid latitude and longitude ranges.
+func ParseCoordinates(query string) (Coordinates, bool) {
+	latStr, lonStr, ok := strings.Cut(query, ",")
+	if !ok {
+		return Coordinates{}, false
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(latStr), 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return Coordinates{}, false
+	}
+	lon, err := strconv.ParseFloat(strings.TrimSpace(lonStr), 64)
+	if err != nil || lon < -180 || lon > 180 {
+		return Coordinates{}, false
+	}
+	return Coordinates{Lat: lat, Lon: lon}, true
+}
@@ -0,0 +1,52 @@
+package model
+
+type OpenMeteoGeocodingResponse struct {
+	Results []OpenMeteoGeocodingResult `json:"results"`
+}
+
+type OpenMeteoGeocodingResult struct {
+	ID        int64   `json:"id"`
+	Name      string  `json:"name"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Country   string  `json:"country"`
+	Admin1    string  `json:"admin1"`
+	Timezone  string  `json:"timezone"`
+}
+
+type OpenMeteoForecastResponse struct {
+	Timezone string `json:"timezone"`
+	Current  struct {
+		Time                int64   `json:"time"`
+		Temperature2m       float64 `json:"temperature_2m"`
+		ApparentTemperature float64 `json:"apparent_temperature"`
+		RelativeHumidity2m  float64 `json:"relative_humidity_2m"`
+		WeatherCode         int     `json:"weather_code"`
+		WindSpeed10m        float64 `json:"wind_speed_10m"`
+		WindDirection10m    int     `json:"wind_direction_10m"`
+		WindGusts10m        float64 `json:"wind_gusts_10m"`
+		PressureMsl         float64 `json:"pressure_msl"`
+		Precipitation       float64 `json:"precipitation"`
+		CloudCover          int     `json:"cloud_cover"`
+		Visibility          float64 `json:"visibility"`
+		UVIndex             float64 `json:"uv_index"`
+	} `json:"current"`
+	Daily struct {
+		Time                        []string  `json:"time"`
+		Temperature2mMax            []float64 `json:"temperature_2m_max"`
+		Temperature2mMin            []float64 `json:"temperature_2m_min"`
+		PrecipitationProbabilityMax []float64 `json:"precipitation_probability_max"`
+		WeatherCode                 []int     `json:"weather_code"`
+		WindSpeed10mMax             []float64 `json:"wind_speed_10m_max"`
+		Sunrise                     []string  `json:"sunrise"`
+		Sunset                      []string  `json:"sunset"`
+	} `json:"daily"`
+	Hourly struct {
+		Time                     []int64   `json:"time"`
+		Temperature2m            []float64 `json:"temperature_2m"`
+		RelativeHumidity2m       []float64 `json:"relative_humidity_2m"`
+		Precipitation            []float64 `json:"precipitation"`
+		PrecipitationProbability []float64 `json:"precipitation_probability"`
+		WeatherCode              []int     `json:"weather_code"`
+	} `json:"hourly"`
+}
@@ -0,0 +1,67 @@
+package model
+
+type OpenWeatherMapResponse struct {
+	Name  string `json:"name"`
+	Coord struct {
+		Lat float64 `json:"lat"`
+		Lon float64 `json:"lon"`
+	} `json:"coord"`
+	Dt   int64 `json:"dt"`
+	Main struct {
+		Temp      float64 `json:"temp"`
+		FeelsLike float64 `json:"feels_like"`
+		Humidity  float64 `json:"humidity"`
+		Pressure  float64 `json:"pressure"`
+	} `json:"main"`
+	Weather []OpenWeatherMapCondition `json:"weather"`
+	Wind    struct {
+		Speed float64 `json:"speed"`
+		Deg   int     `json:"deg"`
+		Gust  float64 `json:"gust"`
+	} `json:"wind"`
+	Clouds struct {
+		All int `json:"all"`
+	} `json:"clouds"`
+	Visibility float64 `json:"visibility"`
+	Rain       struct {
+		OneHour float64 `json:"1h"`
+	} `json:"rain"`
+	Snow struct {
+		OneHour float64 `json:"1h"`
+	} `json:"snow"`
+}
+
+type OpenWeatherMapGeocodingResult struct {
+	Name    string  `json:"name"`
+	Lat     float64 `json:"lat"`
+	Lon     float64 `json:"lon"`
+	Country string  `json:"country"`
+	State   string  `json:"state"`
+}
+
+type OpenWeatherMapCondition struct {
+	ID          int    `json:"id"`
+	Description string `json:"description"`
+}
+
+type OpenWeatherMapForecastResponse struct {
+	City struct {
+		Name     string `json:"name"`
+		Timezone int    `json:"timezone"`
+	} `json:"city"`
+	List []struct {
+		Dt   int64 `json:"dt"`
+		Main struct {
+			TempMin float64 `json:"temp_min"`
+			TempMax float64 `json:"temp_max"`
+		} `json:"main"`
+		Weather []OpenWeatherMapCondition `json:"weather"`
+		Wind    struct {
+			Speed float64 `json:"speed"`
+		} `json:"wind"`
+		Pop  float64 `json:"pop"`
+		Rain struct {
+			ThreeHours float64 `json:"3h"`
+		} `json:"rain"`
+	} `json:"list"`
+}
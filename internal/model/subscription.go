@@ -1,9 +1,70 @@
 package model
 
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	FrequencyHourly = "hourly"
+	FrequencyDaily  = "daily"
+	FrequencyAlerts = "alerts"
+)
+
+// Subscription is a request for weather emails about a city. When Latitude and Longitude
+// are given instead of a city name, City holds the "lat,lon" location query built from them.
+// Location is the canonical place the city resolved to; it is set by the server.
 type Subscription struct {
-	Email     string `json:"email"`
-	City      string `json:"city"`
-	Frequency string `json:"frequency"`
-	Token     string `json:"token"`
-	Confirmed bool   `json:"confirmed"`
+	ID                string    `json:"-"`
+	Email             string    `json:"email"`
+	City              string    `json:"city"`
+	Latitude          *float64  `json:"latitude,omitempty"`
+	Longitude         *float64  `json:"longitude,omitempty"`
+	Location          *Location `json:"location,omitempty" swaggerignore:"true"`
+	Frequency         string    `json:"frequency" enums:"hourly,daily,alerts"`
+	IncludeAirQuality bool      `json:"include_air_quality"`
+	Units             string    `json:"units,omitempty" enums:"metric,imperial,scientific" default:"metric"`
+	Language          string    `json:"language,omitempty" enums:"en,uk"`
+	Token             string    `json:"token"`
+	Confirmed         bool      `json:"confirmed"`
+	// NextRunAt is when the scheduler next sends the update or checks the alerts, nil if not scheduled.
+	NextRunAt  *time.Time `json:"-"`
+	LastSentAt *time.Time `json:"-"`
+}
+
+// SetLocation stores the canonical location on the subscription, and its coordinates unless
+// the subscriber gave their own.
+func (s *Subscription) SetLocation(location *Location) {
+	s.Location = location
+	if s.Latitude == nil || s.Longitude == nil {
+		s.Latitude = &location.Lat
+		s.Longitude = &location.Lon
+	}
+}
+
+// LocationQuery returns the query used to look up weather for the subscription:
+// the coordinates when they are known, otherwise the city as entered.
+func (s *Subscription) LocationQuery() string {
+	if s.Latitude != nil && s.Longitude != nil {
+		return Coordinates{Lat: *s.Latitude, Lon: *s.Longitude}.String()
+	}
+	return s.City
+}
+
+// DisplayName returns the canonical location name when resolved, otherwise the city as entered.
+func (s *Subscription) DisplayName() string {
+	if s.Location != nil && s.Location.Name != "" {
+		return s.Location.Name
+	}
+	return s.City
+}
+
+// Key identifies the subscribed place of an email: the canonical location ID when resolved,
+// otherwise the case-insensitive city name.
+func (s *Subscription) Key() string {
+	if s.Location != nil && s.Location.ID != "" {
+		return fmt.Sprintf("%s|%s", s.Email, s.Location.ID)
+	}
+	return fmt.Sprintf("%s|%s", s.Email, strings.ToLower(s.City))
 }
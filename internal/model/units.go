@@ -0,0 +1,17 @@
+package model
+
+const (
+	UnitsMetric     = "metric"
+	UnitsImperial   = "imperial"
+	UnitsScientific = "scientific"
+)
+
+// Units names the unit of each converted quantity in a response.
+type Units struct {
+	System        string `json:"system" enums:"metric,imperial,scientific"`
+	Temperature   string `json:"temperature" example:"°C"`
+	WindSpeed     string `json:"wind_speed" example:"km/h"`
+	Pressure      string `json:"pressure" example:"hPa"`
+	Precipitation string `json:"precipitation" example:"mm"`
+	Visibility    string `json:"visibility" example:"km"`
+}
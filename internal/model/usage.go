@@ -0,0 +1,26 @@
+package model
+
+// Usage is the number of outbound calls made to a weather provider in a day and in a month.
+type Usage struct {
+	Daily   int64
+	Monthly int64
+}
+
+// ProviderUsage reports the consumption of a weather provider's API budget.
+// Budgets of zero are unlimited and omitted.
+type ProviderUsage struct {
+	Provider      string `json:"provider" example:"weatherapi"`
+	Day           string `json:"day" example:"2025-05-17"`
+	DailyCalls    int64  `json:"daily_calls" example:"1234"`
+	DailyBudget   int64  `json:"daily_budget,omitempty" example:"30000"`
+	Month         string `json:"month" example:"2025-05"`
+	MonthlyCalls  int64  `json:"monthly_calls" example:"31337"`
+	MonthlyBudget int64  `json:"monthly_budget,omitempty" example:"1000000"`
+	// Degraded is true once the degrade share of a budget is used up:
+	// cached data is then served rather than refreshed.
+	Degraded bool `json:"degraded"`
+	// Throttled is true once the background share of a budget is used up:
+	// scheduled sends then no longer call the provider, interactive requests still do.
+	Throttled bool `json:"throttled"`
+	Exhausted bool `json:"exhausted"`
+}
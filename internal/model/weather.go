@@ -1,17 +1,68 @@
 package model
 
+import "time"
+
 type WeatherAPIResponse struct {
 	Current struct {
-		TempC     float64 `json:"temp_c"`
-		Humidity  float64 `json:"humidity"`
-		Condition struct {
+		LastUpdatedEpoch int64   `json:"last_updated_epoch"`
+		TempC            float64 `json:"temp_c"`
+		FeelsLikeC       float64 `json:"feelslike_c"`
+		Humidity         float64 `json:"humidity"`
+		WindKph          float64 `json:"wind_kph"`
+		WindDegree       int     `json:"wind_degree"`
+		GustKph          float64 `json:"gust_kph"`
+		PressureMb       float64 `json:"pressure_mb"`
+		PrecipMm         float64 `json:"precip_mm"`
+		Cloud            int     `json:"cloud"`
+		VisKm            float64 `json:"vis_km"`
+		UV               float64 `json:"uv"`
+		Condition        struct {
 			Text string `json:"text"`
 		} `json:"condition"`
+		AirQuality struct {
+			PM25         float64 `json:"pm2_5"`
+			PM10         float64 `json:"pm10"`
+			O3           float64 `json:"o3"`
+			NO2          float64 `json:"no2"`
+			USEPAIndex   int     `json:"us-epa-index"`
+			GBDEFRAIndex int     `json:"gb-defra-index"`
+		} `json:"air_quality"`
 	} `json:"current"`
 }
 
+// Weather is the current weather. Quantities are in metric units (°C, km/h, hPa, mm, km)
+// until converted with the units package. WindDirection is the compass direction the wind
+// blows from in degrees, CloudCover is a percentage, and UVIndex is nil when the provider
+// does not report it.
 type Weather struct {
-	Temperature float64 `json:"temperature"`
-	Humidity    float64 `json:"humidity"`
-	Description string  `json:"description"`
+	Temperature   float64     `json:"temperature" example:"15.8"`
+	FeelsLike     float64     `json:"feels_like" example:"14.2"`
+	Humidity      float64     `json:"humidity" example:"52"`
+	Description   string      `json:"description" example:"Patchy rain nearby"`
+	WindSpeed     float64     `json:"wind_speed" example:"14.4"`
+	WindDirection int         `json:"wind_direction" example:"270"`
+	WindGust      float64     `json:"wind_gust" example:"22.3"`
+	Pressure      float64     `json:"pressure" example:"1012"`
+	Precipitation float64     `json:"precipitation" example:"0.2"`
+	CloudCover    int         `json:"cloud_cover" example:"75"`
+	Visibility    float64     `json:"visibility" example:"10"`
+	UVIndex       *float64    `json:"uv_index,omitempty" example:"3"`
+	ObservedAt    time.Time   `json:"observed_at"`
+	Sources       []string    `json:"sources,omitempty"`
+	AirQuality    *AirQuality `json:"air_quality,omitempty"`
+	Units         *Units      `json:"units,omitempty"`
+	Cache         *CacheInfo  `json:"-"`
+}
+
+// CacheInfo describes how a weather response was served by the cache.
+type CacheInfo struct {
+	Status string
+	Age    time.Duration
+}
+
+type WeatherAPIErrorResponse struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
 }
@@ -0,0 +1,75 @@
+// Package notification composes the emails sent to subscribers.
+package notification
+
+import (
+	"html"
+	"time"
+
+	"Weather-API-Application/internal/i18n"
+	"Weather-API-Application/internal/model"
+	"Weather-API-Application/internal/utils/units"
+)
+
+// Message is a composed email.
+type Message struct {
+	Subject string
+	Body    string
+}
+
+// Composer builds update and alert emails in the units and language of each subscription.
+type Composer struct{}
+
+func NewComposer() *Composer {
+	return &Composer{}
+}
+
+// Update composes a weather update. airQuality is optional and only included when not nil.
+func (c *Composer) Update(sub *model.Subscription, weather *model.Weather, airQuality *model.AirQuality) Message {
+	system := subscriptionUnits(sub)
+	lang := subscriptionLanguage(sub)
+
+	// Bodies are HTML, so text that comes from subscribers or providers is escaped
+	body := lang.Message(i18n.UpdateBody,
+		html.EscapeString(sub.DisplayName()), system.Temperature(weather.Temperature), html.EscapeString(system.Labels().Temperature),
+		weather.Humidity, html.EscapeString(lang.Text(weather.Description)))
+	if airQuality != nil {
+		body += lang.Message(i18n.UpdateAirQuality,
+			html.EscapeString(lang.Text(airQuality.USEPACategory)), airQuality.USEPAIndex, airQuality.GBDEFRAIndex,
+			airQuality.PM25, airQuality.PM10, airQuality.O3, airQuality.NO2)
+	}
+
+	return Message{
+		Subject: lang.Message(i18n.UpdateSubject, sub.DisplayName()),
+		Body:    body,
+	}
+}
+
+// Alert composes a severe weather alert.
+func (c *Composer) Alert(sub *model.Subscription, alert model.Alert) Message {
+	lang := subscriptionLanguage(sub)
+	return Message{
+		Subject: lang.Message(i18n.AlertSubject, sub.DisplayName(), alert.Event),
+		Body: lang.Message(i18n.AlertBody,
+			html.EscapeString(alert.Headline), html.EscapeString(alert.Event), html.EscapeString(alert.Severity), html.EscapeString(alert.Areas),
+			html.EscapeString(alert.Effective.Format(time.RFC1123)), html.EscapeString(alert.Expires.Format(time.RFC1123)),
+			html.EscapeString(alert.Description)),
+	}
+}
+
+// subscriptionUnits returns the unit system of the subscription, metric if it is unknown.
+func subscriptionUnits(sub *model.Subscription) units.System {
+	system, ok := units.Parse(sub.Units)
+	if !ok {
+		return units.Metric
+	}
+	return system
+}
+
+// subscriptionLanguage returns the language of the subscription, the default if it is unsupported.
+func subscriptionLanguage(sub *model.Subscription) i18n.Language {
+	lang, ok := i18n.Parse(sub.Language)
+	if !ok {
+		return i18n.Default
+	}
+	return lang
+}
@@ -0,0 +1,26 @@
+package notification
+
+import (
+	"testing"
+
+	"Weather-API-Application/internal/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestComposerEscapesHTML(t *testing.T) {
+	sub := &model.Subscription{City: `<script>alert("x")</script>`}
+	composer := NewComposer()
+
+	update := composer.Update(sub, &model.Weather{Description: "Rain & <b>wind</b>"}, nil)
+	require.Contains(t, update.Body, "&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt;")
+	require.Contains(t, update.Body, "Rain &amp; &lt;b&gt;wind&lt;/b&gt;")
+	require.NotContains(t, update.Body, "<script>")
+
+	alert := composer.Alert(sub, model.Alert{Headline: "<i>Storm</i>", Areas: "A & B", Description: "<img src=x>"})
+	require.Contains(t, alert.Body, "<b>&lt;i&gt;Storm&lt;/i&gt;</b>")
+	require.Contains(t, alert.Body, "A &amp; B")
+	require.NotContains(t, alert.Body, "<img")
+	// Subjects are plain text headers, not HTML
+	require.Contains(t, alert.Subject, `<script>alert("x")</script>`)
+}
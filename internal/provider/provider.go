@@ -0,0 +1,76 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"Weather-API-Application/internal/model"
+)
+
+// WeatherProvider abstracts an upstream weather data source.
+type WeatherProvider interface {
+	// Name returns a short identifier of the provider (e.g. "weatherapi").
+	Name() string
+	// CurrentWeather returns the current weather for the given location query.
+	CurrentWeather(ctx context.Context, location string) (*model.Weather, error)
+	// Forecast returns a daily forecast for the given number of days starting today.
+	Forecast(ctx context.Context, location string, days int) (*model.Forecast, error)
+	// HourlyForecast returns forecast points between from and to, inclusive.
+	HourlyForecast(ctx context.Context, location string, from, to time.Time) (*model.HourlyForecast, error)
+	// History returns the observed weather for a past date.
+	History(ctx context.Context, location string, date time.Time) (*model.History, error)
+	// AirQuality returns current pollutant concentrations and air quality indices.
+	AirQuality(ctx context.Context, location string) (*model.AirQuality, error)
+	// Alerts returns the currently active government weather alerts.
+	Alerts(ctx context.Context, location string) (*model.Alerts, error)
+	// Astronomy returns sunrise, sunset, moonrise, moonset and moon phase for a date.
+	Astronomy(ctx context.Context, location string, date time.Time) (*model.Astronomy, error)
+	// SearchLocations returns places matching a partial name, best matches first.
+	SearchLocations(ctx context.Context, query string) ([]model.Location, error)
+	// ResolveLocation returns the canonical location for a city name or "lat,lon" query.
+	ResolveLocation(ctx context.Context, query string) (*model.Location, error)
+}
+
+var (
+	ErrLocationNotFound = errors.New("location not found")
+	ErrNotSupported     = errors.New("operation not supported by provider")
+	ErrNoData           = errors.New("no weather data for the requested period")
+	ErrQuotaExceeded    = errors.New("provider quota exceeded")
+)
+
+// UpstreamError is returned when an upstream API responds with an unexpected status code.
+type UpstreamError struct {
+	Provider   string
+	StatusCode int
+}
+
+func (e *UpstreamError) Error() string {
+	return fmt.Sprintf("%s: unexpected upstream response: %d %s", e.Provider, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Is reports a 429 Too Many Requests response as ErrQuotaExceeded.
+func (e *UpstreamError) Is(target error) bool {
+	return target == ErrQuotaExceeded && e.StatusCode == http.StatusTooManyRequests
+}
+
+// IsTransient reports whether err is a temporary upstream failure such as a timeout,
+// a network error, rate limiting or a 5xx response, so another attempt may succeed.
+func IsTransient(err error) bool {
+	if errors.Is(err, ErrQuotaExceeded) {
+		return true
+	}
+	var upstreamErr *UpstreamError
+	if errors.As(err, &upstreamErr) {
+		return upstreamErr.StatusCode >= http.StatusInternalServerError ||
+			upstreamErr.StatusCode == http.StatusTooManyRequests
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,280 @@
+// Package quota counts outbound weather provider calls and enforces per-provider budgets.
+package quota
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"Weather-API-Application/internal/config"
+	"Weather-API-Application/internal/logger"
+	"Weather-API-Application/internal/model"
+	"Weather-API-Application/internal/provider"
+	"Weather-API-Application/internal/repository"
+)
+
+// ErrBudgetExhausted is returned for calls refused by the meter. It matches
+// provider.ErrQuotaExceeded, so callers handle it like an upstream quota error.
+var ErrBudgetExhausted = fmt.Errorf("weather API budget exhausted: %w", provider.ErrQuotaExceeded)
+
+// Budget limits the calls to a provider per UTC day and month. Zero means unlimited.
+type Budget struct {
+	Daily   int64
+	Monthly int64
+}
+
+// Meter counts calls per provider and refuses calls over budget. Calls are counted in memory
+// and added to the usage repository by Flush, so a slow or failing database never delays them.
+// Background calls are refused once they would eat into the reserve, the fraction of
+// each budget kept for interactive calls, and calls with a fallback once the degrade
+// fraction is used.
+type Meter struct {
+	usage   repository.UsageRepository
+	budgets map[string]Budget
+	reserve float64
+	degrade float64
+	now     func() time.Time
+
+	mu      sync.Mutex
+	counts  map[string]*counter
+	pending map[period]int64
+}
+
+// counter caches the usage of a provider in the current day and month, including pending calls.
+type counter struct {
+	day, month string
+	usage      model.Usage
+}
+
+// period identifies the day and month a provider's pending calls were made in.
+type period struct {
+	provider, day, month string
+}
+
+// BudgetsFromConfig combines the daily and monthly budgets configured per provider.
+func BudgetsFromConfig(cfg *config.Config) map[string]Budget {
+	budgets := make(map[string]Budget)
+	for name, daily := range cfg.WeatherDailyBudgets {
+		b := budgets[name]
+		b.Daily = daily
+		budgets[name] = b
+	}
+	for name, monthly := range cfg.WeatherMonthlyBudgets {
+		b := budgets[name]
+		b.Monthly = monthly
+		budgets[name] = b
+	}
+	return budgets
+}
+
+func NewMeter(usage repository.UsageRepository, budgets map[string]Budget, reserve, degrade float64) *Meter {
+	return &Meter{
+		usage:   usage,
+		budgets: budgets,
+		reserve: reserve,
+		degrade: degrade,
+		now:     time.Now,
+		counts:  make(map[string]*counter),
+		pending: make(map[period]int64),
+	}
+}
+
+// Allow returns ErrBudgetExhausted if a call to the provider with the priority of ctx
+// would exceed its budget.
+func (m *Meter) Allow(ctx context.Context, name string) error {
+	budget, ok := m.budgets[name]
+	if !ok {
+		return nil
+	}
+	usage := m.current(ctx, name)
+
+	limit := 1.0
+	if PriorityFrom(ctx) == Background {
+		limit -= m.reserve
+	}
+	if HasFallback(ctx) {
+		limit = min(limit, m.degrade)
+	}
+	if over(usage.Daily, budget.Daily, limit) || over(usage.Monthly, budget.Monthly, limit) {
+		return fmt.Errorf("%s: %w", name, ErrBudgetExhausted)
+	}
+	return nil
+}
+
+// Record counts one call to the provider. The call is stored by the next Flush.
+func (m *Meter) Record(name string) {
+	day, month := m.periods()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c := m.counter(name, day, month)
+	c.usage.Daily++
+	c.usage.Monthly++
+	m.pending[period{provider: name, day: day, month: month}]++
+}
+
+// Run flushes the recorded calls every interval until ctx is cancelled, and once more then.
+func (m *Meter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.Flush(ctx)
+		case <-ctx.Done():
+			m.Flush(context.WithoutCancel(ctx))
+			return
+		}
+	}
+}
+
+// Flush adds the calls recorded since the last flush to the usage repository and picks up
+// the calls counted by other instances. Calls that fail to be stored are kept for the next flush.
+func (m *Meter) Flush(ctx context.Context) {
+	m.mu.Lock()
+	pending := m.pending
+	m.pending = make(map[period]int64)
+	m.mu.Unlock()
+
+	for p, calls := range pending {
+		usage, err := m.usage.Add(ctx, p.provider, p.day, p.month, calls)
+
+		m.mu.Lock()
+		if err != nil {
+			m.pending[p] += calls
+		} else if c, ok := m.counts[p.provider]; ok && c.day == p.day && c.month == p.month {
+			// Calls recorded during the flush are not stored yet
+			recorded := m.pending[p]
+			c.usage.Daily = max(c.usage.Daily, usage.Daily+recorded)
+			c.usage.Monthly = max(c.usage.Monthly, usage.Monthly+recorded)
+		}
+		m.mu.Unlock()
+
+		if err != nil {
+			logger.Error(ctx, fmt.Errorf("failed to record weather provider usage: %w", err), slog.String("provider", p.provider))
+		}
+	}
+}
+
+// Report returns the usage of each provider in the current day and month.
+func (m *Meter) Report(ctx context.Context, names []string) ([]model.ProviderUsage, error) {
+	day, month := m.periods()
+	report := make([]model.ProviderUsage, 0, len(names))
+	for _, name := range names {
+		usage, err := m.usage.Get(ctx, name, day, month)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load usage of %s: %w", name, err)
+		}
+		m.mu.Lock()
+		pending := m.pendingUsage(name, day, month)
+		m.mu.Unlock()
+		usage.Daily += pending.Daily
+		usage.Monthly += pending.Monthly
+		budget := m.budgets[name]
+		report = append(report, model.ProviderUsage{
+			Provider:      name,
+			Day:           day,
+			DailyCalls:    usage.Daily,
+			DailyBudget:   budget.Daily,
+			Month:         month,
+			MonthlyCalls:  usage.Monthly,
+			MonthlyBudget: budget.Monthly,
+			Degraded:      over(usage.Daily, budget.Daily, m.degrade) || over(usage.Monthly, budget.Monthly, m.degrade),
+			Throttled:     over(usage.Daily, budget.Daily, 1-m.reserve) || over(usage.Monthly, budget.Monthly, 1-m.reserve),
+			Exhausted:     over(usage.Daily, budget.Daily, 1) || over(usage.Monthly, budget.Monthly, 1),
+		})
+	}
+	return report, nil
+}
+
+// current returns the known usage of the provider, loading it on the first call of a day.
+func (m *Meter) current(ctx context.Context, name string) model.Usage {
+	day, month := m.periods()
+
+	m.mu.Lock()
+	c, ok := m.counts[name]
+	loaded := ok && c.day == day
+	if loaded {
+		defer m.mu.Unlock()
+		return c.usage
+	}
+	m.mu.Unlock()
+
+	// Usage may have been counted by other instances or before a restart
+	usage, err := m.usage.Get(ctx, name, day, month)
+	if err != nil {
+		logger.Error(ctx, fmt.Errorf("failed to load weather provider usage: %w", err), slog.String("provider", name))
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c = m.counter(name, day, month)
+	if err == nil {
+		pending := m.pendingUsage(name, day, month)
+		c.usage.Daily = max(c.usage.Daily, usage.Daily+pending.Daily)
+		c.usage.Monthly = max(c.usage.Monthly, usage.Monthly+pending.Monthly)
+	}
+	return c.usage
+}
+
+// pendingUsage returns the calls to the provider in the day and month that are not stored yet.
+// It must be called with mu held.
+func (m *Meter) pendingUsage(name, day, month string) model.Usage {
+	var usage model.Usage
+	for p, calls := range m.pending {
+		if p.provider != name {
+			continue
+		}
+		if p.day == day {
+			usage.Daily += calls
+		}
+		if p.month == month {
+			usage.Monthly += calls
+		}
+	}
+	return usage
+}
+
+// counter returns the counter of the provider for the day, starting a new one when
+// the day or month changed. It must be called with mu held.
+func (m *Meter) counter(name, day, month string) *counter {
+	c, ok := m.counts[name]
+	if !ok || c.month != month {
+		c = &counter{day: day, month: month}
+		m.counts[name] = c
+	} else if c.day != day {
+		c.day = day
+		c.usage.Daily = 0
+	}
+	return c
+}
+
+func (m *Meter) periods() (day, month string) {
+	now := m.now().UTC()
+	return now.Format(time.DateOnly), now.Format("2006-01")
+}
+
+// over reports whether calls reached the given fraction of budget. A zero budget is unlimited.
+func over(calls, budget int64, fraction float64) bool {
+	return budget > 0 && float64(calls) >= float64(budget)*fraction
+}
+
+// Transport is an http.RoundTripper that enforces and counts the budget of one provider.
+type Transport struct {
+	Base     http.RoundTripper
+	Meter    *Meter
+	Provider string
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.Meter.Allow(req.Context(), t.Provider); err != nil {
+		return nil, err
+	}
+	t.Meter.Record(t.Provider)
+	return t.Base.RoundTrip(req)
+}
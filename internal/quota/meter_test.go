@@ -0,0 +1,122 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"Weather-API-Application/internal/model"
+	"Weather-API-Application/internal/provider"
+
+	"github.com/stretchr/testify/require"
+)
+
+// memoryUsage is an in-memory repository.UsageRepository.
+type memoryUsage map[string]int64
+
+func (u memoryUsage) Add(_ context.Context, name, day, month string, calls int64) (model.Usage, error) {
+	u[name+"|"+day] += calls
+	u[name+"|"+month] += calls
+	return model.Usage{Daily: u[name+"|"+day], Monthly: u[name+"|"+month]}, nil
+}
+
+func (u memoryUsage) Get(_ context.Context, name, day, month string) (model.Usage, error) {
+	return model.Usage{Daily: u[name+"|"+day], Monthly: u[name+"|"+month]}, nil
+}
+
+func TestMeter(t *testing.T) {
+	now := time.Date(2025, 5, 17, 12, 0, 0, 0, time.UTC)
+	usage := memoryUsage{"weatherapi|2025-05": 5}
+	m := NewMeter(usage, map[string]Budget{"weatherapi": {Daily: 10, Monthly: 20}}, 0.2, 1)
+	m.now = func() time.Time { return now }
+
+	interactive := context.Background()
+	background := WithPriority(interactive, Background)
+
+	for range 7 {
+		require.NoError(t, m.Allow(background, "weatherapi"))
+		m.Record("weatherapi")
+	}
+	// 8 of 10 daily calls: the rest is reserved for interactive calls
+	m.Record("weatherapi")
+	require.ErrorIs(t, m.Allow(background, "weatherapi"), ErrBudgetExhausted)
+	require.ErrorIs(t, m.Allow(background, "weatherapi"), provider.ErrQuotaExceeded)
+	require.NoError(t, m.Allow(interactive, "weatherapi"))
+	m.Record("weatherapi")
+	m.Record("weatherapi")
+	require.ErrorIs(t, m.Allow(interactive, "weatherapi"), ErrBudgetExhausted)
+
+	// Providers without a budget are only counted
+	require.NoError(t, m.Allow(background, "openmeteo"))
+
+	// A new day resets the daily count; the month continues at 15 of 20 calls
+	now = now.Add(24 * time.Hour)
+	require.NoError(t, m.Allow(background, "weatherapi"))
+	m.Flush(interactive)
+	require.Equal(t, int64(10), usage["weatherapi|2025-05-17"])
+
+	report, err := m.Report(interactive, []string{"weatherapi"})
+	require.NoError(t, err)
+	require.Equal(t, []model.ProviderUsage{{
+		Provider:      "weatherapi",
+		Day:           "2025-05-18",
+		DailyBudget:   10,
+		Month:         "2025-05",
+		MonthlyCalls:  15,
+		MonthlyBudget: 20,
+	}}, report)
+}
+
+// failingUsage is a repository.UsageRepository whose writes fail until it is fixed.
+type failingUsage struct {
+	memoryUsage
+	broken bool
+}
+
+func (u *failingUsage) Add(ctx context.Context, name, day, month string, calls int64) (model.Usage, error) {
+	if u.broken {
+		return model.Usage{}, errors.New("database unavailable")
+	}
+	return u.memoryUsage.Add(ctx, name, day, month, calls)
+}
+
+func TestMeterFlushFailure(t *testing.T) {
+	usage := &failingUsage{memoryUsage: memoryUsage{}, broken: true}
+	m := NewMeter(usage, map[string]Budget{"weatherapi": {Daily: 3}}, 0, 1)
+	ctx := context.Background()
+
+	// Calls are counted and limited while the usage cannot be stored
+	for range 3 {
+		require.NoError(t, m.Allow(ctx, "weatherapi"))
+		m.Record("weatherapi")
+	}
+	m.Flush(ctx)
+	require.ErrorIs(t, m.Allow(ctx, "weatherapi"), ErrBudgetExhausted)
+
+	// and stored by the next flush that succeeds
+	usage.broken = false
+	m.Flush(ctx)
+	day, month := m.periods()
+	stored, err := usage.Get(ctx, "weatherapi", day, month)
+	require.NoError(t, err)
+	require.Equal(t, model.Usage{Daily: 3, Monthly: 3}, stored)
+}
+
+func TestMeterDegrade(t *testing.T) {
+	m := NewMeter(memoryUsage{}, map[string]Budget{"weatherapi": {Daily: 10}}, 0.2, 0.5)
+	ctx := context.Background()
+	for range 5 {
+		m.Record("weatherapi")
+	}
+
+	// Half the budget is used: calls that can fall back to cached data stop first
+	require.ErrorIs(t, m.Allow(WithFallback(ctx), "weatherapi"), ErrBudgetExhausted)
+	require.NoError(t, m.Allow(ctx, "weatherapi"))
+	require.NoError(t, m.Allow(WithPriority(ctx, Background), "weatherapi"))
+
+	report, err := m.Report(ctx, []string{"weatherapi"})
+	require.NoError(t, err)
+	require.True(t, report[0].Degraded)
+	require.False(t, report[0].Throttled)
+}
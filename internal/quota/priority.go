@@ -0,0 +1,40 @@
+package quota
+
+import "context"
+
+// Priority decides how much of a provider budget a call may use.
+type Priority int
+
+const (
+	// Interactive calls serve API clients and may use the whole budget. It is the default.
+	Interactive Priority = iota
+	// Background calls, such as scheduled emails, stop before the interactive reserve.
+	Background
+)
+
+type priorityKey struct{}
+
+// WithPriority returns a context whose upstream calls are made with priority p.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// PriorityFrom returns the priority of ctx, Interactive if none was set.
+func PriorityFrom(ctx context.Context) Priority {
+	p, _ := ctx.Value(priorityKey{}).(Priority)
+	return p
+}
+
+type fallbackKey struct{}
+
+// WithFallback returns a context whose upstream calls can be replaced by older data the
+// caller already holds, such as a cached value. They stop once the degrade share of a budget is used.
+func WithFallback(ctx context.Context) context.Context {
+	return context.WithValue(ctx, fallbackKey{}, true)
+}
+
+// HasFallback reports whether the calls of ctx have a fallback.
+func HasFallback(ctx context.Context) bool {
+	fallback, _ := ctx.Value(fallbackKey{}).(bool)
+	return fallback
+}
@@ -3,8 +3,17 @@ package repository
 import (
 	"Weather-API-Application/internal/model"
 	"context"
+	"errors"
+	"time"
 )
 
+// ErrDuplicate is returned when a write would create a second subscription
+// of the same email to the same location.
+var ErrDuplicate = errors.New("subscription already exists")
+
+// ErrNotFound is returned when no subscription matches the given token or ID.
+var ErrNotFound = errors.New("subscription not found")
+
 type SubscriptionRepository interface {
 	CheckConfirmation(ctx context.Context, subscriptionRequest *model.Subscription) (rowExists bool, confirmed bool, err error)
 	Create(ctx context.Context, subscriptionRequest *model.Subscription) error
@@ -13,4 +22,31 @@ type SubscriptionRepository interface {
 	SetConfirmed(ctx context.Context, subId string) error
 	DeleteByToken(ctx context.Context, token string) error
 	ListConfirmed(ctx context.Context) ([]*model.Subscription, error)
+	ListUnresolved(ctx context.Context, maxAttempts, limit int) ([]*model.Subscription, error)
+	RecordLocationFailure(ctx context.Context, subId string) error
+	SetLocation(ctx context.Context, subId string, location *model.Location) error
+	MarkAlertSent(ctx context.Context, subId string, alertId string) (marked bool, err error)
+	UnmarkAlertSent(ctx context.Context, subId string, alertId string) error
+	SetNextRun(ctx context.Context, subId string, nextRun *time.Time) error
+	ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration, next NextRunFunc) ([]*model.Delivery, error)
+	StartDelivery(ctx context.Context, subId string, slot time.Time, now time.Time) (started bool, err error)
+	CompleteDelivery(ctx context.Context, subId string, slot time.Time, sendErr error) error
+	ListUnscheduled(ctx context.Context) ([]*model.Subscription, error)
+	ClearUnconfirmedSchedules(ctx context.Context) (cleared int64, err error)
+	FailAbandonedDeliveries(ctx context.Context, claimedBefore time.Time) (failed int64, err error)
+}
+
+// NextRunFunc returns the run of a subscription that follows the claimed slot.
+type NextRunFunc func(sub *model.Subscription, slot time.Time) time.Time
+
+type ObservationRepository interface {
+	Save(ctx context.Context, observation *model.Observation) error
+	ListByCity(ctx context.Context, city string, from, to time.Time) ([]*model.Observation, error)
+}
+
+// UsageRepository counts outbound weather provider calls per day and month.
+// Periods are formatted as "2006-01-02" (day) and "2006-01" (month).
+type UsageRepository interface {
+	Add(ctx context.Context, provider, day, month string, calls int64) (model.Usage, error)
+	Get(ctx context.Context, provider, day, month string) (model.Usage, error)
 }
@@ -0,0 +1,201 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// State is the state of a circuit breaker.
+type State string
+
+const (
+	// StateClosed lets calls through and counts consecutive failures.
+	StateClosed State = "closed"
+	// StateOpen rejects calls until the open timeout has passed.
+	StateOpen State = "open"
+	// StateHalfOpen lets a single probe call through to decide whether to close again.
+	StateHalfOpen State = "half-open"
+)
+
+// halfOpenRetryAfter is suggested to callers rejected while a probe call is in flight.
+const halfOpenRetryAfter = time.Second
+
+// ErrOpen matches every OpenError.
+var ErrOpen = errors.New("circuit breaker is open")
+
+// OpenError is returned for calls rejected by an open circuit breaker.
+type OpenError struct {
+	Name       string
+	RetryAfter time.Duration
+}
+
+func (e *OpenError) Error() string {
+	return fmt.Sprintf("%s: %s, retry after %s", e.Name, ErrOpen, e.RetryAfter.Round(time.Second))
+}
+
+func (e *OpenError) Is(target error) bool {
+	return target == ErrOpen
+}
+
+// Status is a snapshot of a circuit breaker for monitoring.
+type Status struct {
+	Name                string     `json:"name" example:"weatherapi"`
+	State               State      `json:"state" enums:"closed,open,half-open"`
+	ConsecutiveFailures int        `json:"consecutive_failures" example:"0"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+	RetryAfterSeconds   int        `json:"retry_after_seconds,omitempty" example:"30"`
+}
+
+// Breaker is a circuit breaker. It opens after FailureThreshold consecutive failures,
+// rejects calls for OpenTimeout, and then lets one probe call decide whether to close
+// again or stay open for another OpenTimeout.
+type Breaker struct {
+	name             string
+	failureThreshold int
+	openTimeout      time.Duration
+	now              func() time.Time
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewBreaker creates a closed breaker. A failureThreshold of zero or less disables it.
+func NewBreaker(name string, failureThreshold int, openTimeout time.Duration) *Breaker {
+	return &Breaker{
+		name:             name,
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		now:              time.Now,
+		state:            StateClosed,
+	}
+}
+
+// Do calls fn unless the breaker is open, in which case it returns an *OpenError.
+// Errors for which isFailure is true count towards opening the breaker; any other result
+// counts as a success. Calls cancelled through ctx count as neither, while calls that
+// ran into the ctx deadline are judged by isFailure like any other.
+func (b *Breaker) Do(ctx context.Context, fn func(ctx context.Context) error, isFailure func(error) bool) error {
+	if err := b.allow(); err != nil {
+		return err
+	}
+
+	err := fn(ctx)
+	switch {
+	case errors.Is(ctx.Err(), context.Canceled):
+		b.release()
+	case err != nil && isFailure(err):
+		b.failure()
+	default:
+		b.success()
+	}
+	return err
+}
+
+// Status returns a snapshot of the breaker.
+func (b *Breaker) Status() Status {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := Status{Name: b.name, State: b.state, ConsecutiveFailures: b.failures}
+	if b.state != StateClosed {
+		openedAt := b.openedAt
+		status.OpenedAt = &openedAt
+	}
+	if b.state == StateOpen {
+		status.RetryAfterSeconds = int(max(b.openTimeout-b.now().Sub(b.openedAt), 0).Seconds())
+	}
+	return status
+}
+
+func (b *Breaker) allow() error {
+	if b.failureThreshold <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if elapsed := b.now().Sub(b.openedAt); elapsed < b.openTimeout {
+			return &OpenError{Name: b.name, RetryAfter: b.openTimeout - elapsed}
+		}
+		b.state = StateHalfOpen
+		b.probing = true
+	case StateHalfOpen:
+		if b.probing {
+			return &OpenError{Name: b.name, RetryAfter: halfOpenRetryAfter}
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+func (b *Breaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = StateClosed
+	b.failures = 0
+	b.probing = false
+}
+
+func (b *Breaker) failure() {
+	if b.failureThreshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probing = false
+	if b.state == StateHalfOpen || b.failures >= b.failureThreshold {
+		b.state = StateOpen
+		b.openedAt = b.now()
+	}
+}
+
+func (b *Breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+// Registry keeps the breakers of an application for monitoring.
+type Registry struct {
+	mu       sync.Mutex
+	breakers []*Breaker
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds a breaker to the registry and returns it.
+func (r *Registry) Register(b *Breaker) *Breaker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.breakers = append(r.breakers, b)
+	return b
+}
+
+// Statuses returns a snapshot of every registered breaker, in registration order.
+func (r *Registry) Statuses() []Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	statuses := make([]Status, 0, len(r.breakers))
+	for _, b := range r.breakers {
+		statuses = append(statuses, b.Status())
+	}
+	return statuses
+}
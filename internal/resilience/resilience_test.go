@@ -0,0 +1,77 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errTransient = errors.New("transient")
+
+func isTransient(err error) bool {
+	return errors.Is(err, errTransient)
+}
+
+func TestRetry(t *testing.T) {
+	policy := Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond}
+	permanent := errors.New("permanent")
+
+	tests := []struct {
+		name      string
+		errs      []error
+		wantErr   error
+		wantCalls int
+	}{
+		{"success", []error{nil}, nil, 1},
+		{"recovers", []error{errTransient, errTransient, nil}, nil, 3},
+		{"gives up", []error{errTransient, errTransient, errTransient}, errTransient, 3},
+		{"permanent error", []error{permanent}, permanent, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := Retry(context.Background(), policy, func(context.Context) error {
+				calls++
+				return tt.errs[calls-1]
+			}, isTransient)
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Equal(t, tt.wantCalls, calls)
+		})
+	}
+}
+
+func TestBreaker(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := NewBreaker("weatherapi", 2, 30*time.Second)
+	b.now = func() time.Time { return now }
+
+	call := func(err error) error {
+		return b.Do(context.Background(), func(context.Context) error { return err }, isTransient)
+	}
+
+	require.ErrorIs(t, call(errTransient), errTransient)
+	require.Equal(t, StateClosed, b.Status().State)
+	require.ErrorIs(t, call(errTransient), errTransient)
+	require.Equal(t, StateOpen, b.Status().State)
+
+	// Open: calls are rejected without reaching the upstream
+	now = now.Add(10 * time.Second)
+	err := call(nil)
+	var openErr *OpenError
+	require.ErrorAs(t, err, &openErr)
+	require.Equal(t, 20*time.Second, openErr.RetryAfter)
+
+	// A failed probe opens the breaker again
+	now = now.Add(20 * time.Second)
+	require.ErrorIs(t, call(errTransient), errTransient)
+	require.Equal(t, StateOpen, b.Status().State)
+	require.ErrorIs(t, call(nil), ErrOpen)
+
+	// A successful probe closes it
+	now = now.Add(30 * time.Second)
+	require.NoError(t, call(nil))
+	require.Equal(t, Status{Name: "weatherapi", State: StateClosed}, b.Status())
+}
@@ -0,0 +1,56 @@
+// Package resilience protects calls to unreliable dependencies with retries and circuit breakers.
+package resilience
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// Policy configures Retry. MaxAttempts includes the first call, so 1 disables retries.
+type Policy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// Retry calls fn until it succeeds, returns an error for which retryable is false,
+// or MaxAttempts calls were made. Between attempts it waits for an exponentially
+// growing delay with full jitter. It gives up early, returning the last error,
+// when ctx is done or its deadline would pass before the next attempt.
+func Retry(ctx context.Context, p Policy, fn func(ctx context.Context) error, retryable func(error) bool) error {
+	var err error
+	for attempt := 0; attempt < max(p.MaxAttempts, 1); attempt++ {
+		if attempt > 0 {
+			delay := p.delay(attempt)
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+				return err
+			}
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return err
+			case <-timer.C:
+			}
+		}
+
+		err = fn(ctx)
+		if err == nil || !retryable(err) || ctx.Err() != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// delay returns a random duration up to BaseDelay * 2^(attempt-1), capped at MaxDelay.
+func (p Policy) delay(attempt int) time.Duration {
+	ceiling := p.BaseDelay << (attempt - 1)
+	if ceiling <= 0 || (p.MaxDelay > 0 && ceiling > p.MaxDelay) {
+		ceiling = p.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling + 1)
+}
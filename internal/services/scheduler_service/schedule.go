@@ -0,0 +1,60 @@
+package scheduler_service
+
+import (
+	"time"
+
+	"Weather-API-Application/internal/model"
+)
+
+// firstRun returns when a newly scheduled subscription is first due: alert checks run
+// immediately, hourly updates an hour from now and daily updates at the next dailyStartHour
+// in the subscriber's time zone.
+func firstRun(sub *model.Subscription, now time.Time, dailyStartHour int) time.Time {
+	switch sub.Frequency {
+	case model.FrequencyAlerts:
+		return now
+	case model.FrequencyDaily:
+		loc := subscriberLocation(sub)
+		local := now.In(loc)
+		next := time.Date(local.Year(), local.Month(), local.Day(), dailyStartHour, 0, 0, 0, loc)
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 1)
+		}
+		return next
+	default:
+		return now.Add(time.Hour)
+	}
+}
+
+// nextRun returns the first run of the subscription after now, following the previous due time,
+// so that runs do not drift and runs missed while the scheduler was busy are skipped.
+func nextRun(sub *model.Subscription, due, now time.Time, alertsPollInterval time.Duration) time.Time {
+	advance := func(t time.Time) time.Time { return t.Add(time.Hour) }
+	switch sub.Frequency {
+	case model.FrequencyAlerts:
+		advance = func(t time.Time) time.Time { return t.Add(alertsPollInterval) }
+	case model.FrequencyDaily:
+		// Days are added in the subscriber's time zone to keep the local hour across DST changes
+		loc := subscriberLocation(sub)
+		advance = func(t time.Time) time.Time { return t.In(loc).AddDate(0, 0, 1) }
+	}
+
+	next := advance(due)
+	for !next.After(now) {
+		next = advance(next)
+	}
+	return next
+}
+
+// subscriberLocation returns the time zone of the subscription's resolved location, or UTC if
+// the location or its time zone is unknown.
+func subscriberLocation(sub *model.Subscription) *time.Location {
+	if sub.Location == nil || sub.Location.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(sub.Location.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
@@ -2,9 +2,9 @@ package scheduler_service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
-	"strings"
 	"sync"
 	"time"
 
@@ -12,122 +12,351 @@ import (
 	"Weather-API-Application/internal/config"
 	"Weather-API-Application/internal/logger"
 	"Weather-API-Application/internal/model"
+	"Weather-API-Application/internal/notification"
+	"Weather-API-Application/internal/quota"
 	"Weather-API-Application/internal/repository"
+	"Weather-API-Application/internal/services/weather_service"
 )
 
-// SchedulerService manages background weather update routines for confirmed subscriptions.
+// errDeliveryTaken is returned when a claimed delivery is no longer pending, because another
+// claim of the same slot started sending it, or it was completed or given up.
+var errDeliveryTaken = errors.New("delivery is no longer pending")
+
+// SchedulerService sends weather updates and alerts for confirmed subscriptions on schedules
+// stored in the database, shared by all instances.
 type SchedulerService struct {
 	repo        repository.SubscriptionRepository
-	emailClient client.EmailClient
+	emailClient client.Client
+	weatherSvc  weather_service.WeatherService
+	composer    *notification.Composer
 	cfg         *config.Config
-	mu          sync.Mutex
-	routines    map[string]context.CancelFunc
+	leader      Leader
+
+	wake    chan struct{}
+	mu      sync.Mutex
+	started bool
 }
 
-func NewSchedulerService(repo repository.SubscriptionRepository, emailClient client.EmailClient, cfg *config.Config) *SchedulerService {
+func NewSchedulerService(repo repository.SubscriptionRepository, emailClient client.Client, weatherSvc weather_service.WeatherService, composer *notification.Composer, cfg *config.Config) *SchedulerService {
 	return &SchedulerService{
 		repo:        repo,
 		emailClient: emailClient,
+		weatherSvc:  weatherSvc,
+		composer:    composer,
 		cfg:         cfg,
-		routines:    make(map[string]context.CancelFunc),
+		wake:        make(chan struct{}, 1),
 	}
 }
 
-// makeKey builds a unique key for a subscription.
-func makeKey(sub *model.Subscription) string {
-	return fmt.Sprintf("%s|%s", sub.Email, strings.ToLower(sub.City))
+// Leader runs a function only on the one instance elected among those sharing the database.
+type Leader interface {
+	// Run runs fn whenever this instance becomes the leader, until ctx is cancelled.
+	// The context passed to fn is cancelled when leadership is lost.
+	Run(ctx context.Context, fn func(ctx context.Context))
+}
+
+// WithLeader runs the reconciliation loop only on the elected leader. Without it, the loop runs
+// on this instance, which is enough when it is the only one.
+func (s *SchedulerService) WithLeader(leader Leader) *SchedulerService {
+	s.leader = leader
+	return s
 }
 
-// StartScheduler starts routines for all confirmed subscriptions.
+// StartScheduler starts the dispatcher, SchedulerWorkers workers and the reconciliation loop,
+// which run until ctx is cancelled. Weather lookups of scheduled sends use the background share
+// of the provider budgets.
 func (s *SchedulerService) StartScheduler(ctx context.Context) error {
-	subs, err := s.repo.ListConfirmed(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to fetch confirmed subscriptions: %w", err)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return fmt.Errorf("scheduler already started")
 	}
+	s.started = true
 
-	for _, sub := range subs {
-		s.StartFor(ctx, sub)
+	runCtx := quota.WithPriority(ctx, quota.Background)
+	work := make(chan *model.Delivery)
+	for range s.cfg.SchedulerWorkers {
+		go s.worker(runCtx, work)
+	}
+	go s.dispatch(runCtx, work)
+	if s.leader != nil {
+		go s.leader.Run(runCtx, s.reconcileLoop)
+	} else {
+		go s.reconcileLoop(runCtx)
 	}
 
-	logger.Info(ctx, "Starting subscription routines",
-		slog.Int("count", len(subs)))
+	logger.Info(ctx, "Scheduler started",
+		slog.Int("workers", s.cfg.SchedulerWorkers),
+		slog.Bool("leader_election", s.leader != nil))
 	return nil
 }
 
-// StartFor starts a routine for a single subscription.
+// StartFor schedules a single subscription from now, replacing its previous schedule if any.
 func (s *SchedulerService) StartFor(ctx context.Context, sub *model.Subscription) {
-	subCtx, cancel := context.WithCancel(ctx)
-	key := makeKey(sub)
-
-	s.mu.Lock()
-	s.routines[key] = cancel
-	s.mu.Unlock()
-
-	go s.StartRoutine(subCtx, sub)
-	logger.Info(ctx, "Routine started", slog.String("email", sub.Email), slog.String("city", sub.City))
+	next := firstRun(sub, time.Now(), s.cfg.DailyStartHour)
+	if err := s.repo.SetNextRun(ctx, sub.ID, &next); err != nil {
+		logger.Error(ctx, fmt.Errorf("failed to schedule subscription: %w", err),
+			slog.String("email", sub.Email),
+			slog.String("city", sub.City))
+		return
+	}
+	s.notify()
+	logger.Info(ctx, "Subscription scheduled",
+		slog.String("email", sub.Email),
+		slog.String("city", sub.City),
+		slog.Time("next_run_at", next))
 }
 
-// StopFor stops a routine for a single subscription if running.
+// StopFor unschedules a single subscription. Deleted subscriptions are never claimed, so this
+// only matters for subscriptions that are kept. A send that is already running is completed.
 func (s *SchedulerService) StopFor(sub *model.Subscription) {
-	key := makeKey(sub)
-	s.mu.Lock()
-	if cancel, ok := s.routines[key]; ok {
-		cancel()
-		delete(s.routines, key)
-	}
-	s.mu.Unlock()
-}
-
-// StartRoutine runs periodic updates for a single subscription until the context is cancelled.
-func (s *SchedulerService) StartRoutine(ctx context.Context, sub *model.Subscription) {
-	// Determine interval
-	interval := time.Hour
-	if strings.ToLower(sub.Frequency) == "daily" {
-		interval = 24 * time.Hour
-
-		// Wait until next scheduled daily start
-		now := time.Now()
-		next := time.Date(
-			now.Year(), now.Month(), now.Day(),
-			s.cfg.DailyStartHour, 0, 0, 0,
-			now.Location(),
-		)
-		if now.After(next) {
-			next = next.Add(24 * time.Hour)
+	ctx := context.Background()
+	if err := s.repo.SetNextRun(ctx, sub.ID, nil); err != nil {
+		logger.Error(ctx, fmt.Errorf("failed to unschedule subscription: %w", err),
+			slog.String("email", sub.Email),
+			slog.String("city", sub.City))
+	}
+}
+
+// reconcileLoop reconciles the schedules right away and then every SchedulerReconcileInterval
+// until ctx is cancelled.
+func (s *SchedulerService) reconcileLoop(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.SchedulerReconcileInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.reconcile(ctx); err != nil && ctx.Err() == nil {
+			logger.Error(ctx, fmt.Errorf("failed to reconcile schedules: %w", err))
 		}
+
 		select {
-		case <-time.After(time.Until(next)):
 		case <-ctx.Done():
-			logger.Info(ctx, "Routine cancelled before first run",
-				slog.String("email", sub.Email),
-				slog.String("city", sub.City))
 			return
+		case <-ticker.C:
 		}
 	}
+}
 
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
+// reconcile repairs schedules that StartFor and StopFor did not keep up to date: confirmed
+// subscriptions without a next run (confirmed before schedules were stored, or while scheduling
+// failed) are scheduled, subscriptions that are no longer confirmed are unscheduled, and deliveries
+// interrupted too many times are marked failed.
+func (s *SchedulerService) reconcile(ctx context.Context) error {
+	subs, err := s.repo.ListUnscheduled(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch unscheduled subscriptions: %w", err)
+	}
+	for _, sub := range subs {
+		next := firstRun(sub, time.Now(), s.cfg.DailyStartHour)
+		if err := s.repo.SetNextRun(ctx, sub.ID, &next); err != nil {
+			return fmt.Errorf("failed to schedule subscription %s: %w", sub.ID, err)
+		}
+	}
+	if len(subs) > 0 {
+		s.notify()
+	}
+
+	cleared, err := s.repo.ClearUnconfirmedSchedules(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to unschedule unconfirmed subscriptions: %w", err)
+	}
+
+	failed, err := s.repo.FailAbandonedDeliveries(ctx, time.Now().Add(-s.cfg.SchedulerDeliveryLease))
+	if err != nil {
+		return fmt.Errorf("failed to fail abandoned deliveries: %w", err)
+	}
+
+	if len(subs) > 0 || cleared > 0 || failed > 0 {
+		logger.Info(ctx, "Schedules reconciled",
+			slog.Int("scheduled", len(subs)),
+			slog.Int64("unscheduled", cleared),
+			slog.Int64("abandoned_deliveries", failed))
+	}
+	return nil
+}
+
+// notify wakes the dispatcher to claim due subscriptions before its next poll.
+func (s *SchedulerService) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// dispatch claims due subscriptions and hands them to the workers. It claims again right away
+// while full batches are due, and otherwise every SchedulerPollInterval or when woken.
+func (s *SchedulerService) dispatch(ctx context.Context, work chan<- *model.Delivery) {
+	following := func(sub *model.Subscription, slot time.Time) time.Time {
+		return nextRun(sub, slot, time.Now(), s.cfg.AlertsPollInterval)
+	}
+	timer := time.NewTimer(s.cfg.SchedulerPollInterval)
+	defer timer.Stop()
+
+	for {
+		deliveries, err := s.repo.ClaimDue(ctx, time.Now(), s.cfg.SchedulerWorkers, s.cfg.SchedulerDeliveryLease, following)
+		if err != nil && ctx.Err() == nil {
+			logger.Error(ctx, fmt.Errorf("failed to claim due subscriptions: %w", err))
+		}
+		for _, d := range deliveries {
+			select {
+			case work <- d:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if err == nil && len(deliveries) == s.cfg.SchedulerWorkers {
+			continue
+		}
 
+		timer.Reset(s.cfg.SchedulerPollInterval)
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
+		case <-timer.C:
+		}
+	}
+}
+
+// worker runs the claimed deliveries handed to it until ctx is cancelled.
+func (s *SchedulerService) worker(ctx context.Context, work <-chan *model.Delivery) {
 	for {
 		select {
 		case <-ctx.Done():
-			logger.Info(ctx, "Stopping routine",
+			return
+		case d := <-work:
+			s.deliver(ctx, d)
+		}
+	}
+}
+
+// deliver runs a claimed delivery within SchedulerDeliveryLease, so that it ends before the slot
+// can be claimed again, and records its outcome. Deliveries interrupted by cancellation or by the
+// lease are not recorded: they are claimed again once the lease expires, and their email is
+// resent with the same Message-ID.
+func (s *SchedulerService) deliver(ctx context.Context, d *model.Delivery) {
+	runCtx, cancel := context.WithTimeout(ctx, s.cfg.SchedulerDeliveryLease)
+	defer cancel()
+
+	sub := d.Subscription
+	sendErr := s.run(runCtx, d)
+	if errors.Is(sendErr, errDeliveryTaken) || sendErr != nil && runCtx.Err() != nil {
+		return
+	}
+	if err := s.repo.CompleteDelivery(ctx, sub.ID, d.Slot, sendErr); err != nil {
+		logger.Error(ctx, fmt.Errorf("failed to record delivery: %w", err),
+			slog.String("email", sub.Email),
+			slog.String("city", sub.City),
+			slog.Time("slot", d.Slot))
+	}
+}
+
+// run sends the update or checks the alerts of a due subscription.
+func (s *SchedulerService) run(ctx context.Context, d *model.Delivery) error {
+	sub := d.Subscription
+	if sub.Frequency == model.FrequencyAlerts {
+		err := s.checkAlerts(ctx, sub)
+		if err != nil {
+			logger.Error(ctx, err,
 				slog.String("email", sub.Email),
 				slog.String("city", sub.City))
-			return
-		case <-ticker.C:
-			logger.Info(ctx, "Attempting to send update",
+		}
+		return err
+	}
+
+	logger.Info(ctx, "Attempting to send update",
+		slog.String("email", sub.Email),
+		slog.String("city", sub.City))
+	if err := s.sendUpdate(ctx, d); err != nil {
+		if errors.Is(err, errDeliveryTaken) {
+			logger.Info(ctx, "Update taken by another claim, skipping",
+				slog.String("email", sub.Email),
+				slog.String("city", sub.City),
+				slog.Time("slot", d.Slot))
+			return err
+		}
+		logger.Error(ctx, err,
+			slog.String("email", sub.Email),
+			slog.String("city", sub.City))
+		return err
+	}
+	logger.Info(ctx, "Weather update sent",
+		slog.String("email", sub.Email),
+		slog.String("city", sub.City))
+	return nil
+}
+
+// sendUpdate emails the current weather, and the air quality if requested, to the subscriber.
+// The delivery is marked as sending first; errDeliveryTaken is returned without sending if it
+// is no longer pending. Every attempt of a slot sends the same Message-ID.
+func (s *SchedulerService) sendUpdate(ctx context.Context, d *model.Delivery) error {
+	sub := d.Subscription
+	weather, err := s.weatherSvc.FetchWeatherForCity(ctx, sub.LocationQuery())
+	if err != nil {
+		return fmt.Errorf("failed to fetch weather: %w", err)
+	}
+
+	var airQuality *model.AirQuality
+	if sub.IncludeAirQuality {
+		// Air quality is an extra: send the weather update even if it is unavailable
+		airQuality, err = s.weatherSvc.FetchAirQuality(ctx, sub.LocationQuery())
+		if err != nil {
+			logger.Error(ctx, fmt.Errorf("failed to fetch air quality: %w", err),
 				slog.String("email", sub.Email),
 				slog.String("city", sub.City))
-			if err := client.SendUpdate(ctx, s.cfg.WeatherApiKey, sub, s.emailClient); err != nil {
-				logger.Error(ctx, err,
-					slog.String("email", sub.Email),
-					slog.String("city", sub.City))
-			} else {
-				logger.Info(ctx, "Weather update sent",
-					slog.String("email", sub.Email),
-					slog.String("city", sub.City))
+			airQuality = nil
+		}
+	}
+
+	msg := s.composer.Update(sub, weather, airQuality)
+	started, err := s.repo.StartDelivery(ctx, sub.ID, d.Slot, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to start delivery: %w", err)
+	}
+	if !started {
+		return errDeliveryTaken
+	}
+	ctx = client.WithMessageID(ctx, fmt.Sprintf("update-%s-%d", sub.ID, d.Slot.Unix()))
+	if err := s.emailClient.SendEmail(ctx, sub.Email, msg.Subject, msg.Body); err != nil {
+		return fmt.Errorf("failed to send email to %s for city %s: %w", sub.Email, sub.City, err)
+	}
+	return nil
+}
+
+// checkAlerts emails every active alert that has not been sent to the subscription yet.
+// Alerts are de-duplicated by ID through the repository, so restarts do not resend them.
+func (s *SchedulerService) checkAlerts(ctx context.Context, sub *model.Subscription) error {
+	alerts, err := s.weatherSvc.FetchAlerts(ctx, sub.LocationQuery())
+	if err != nil {
+		return fmt.Errorf("failed to fetch alerts: %w", err)
+	}
+
+	now := time.Now()
+	for _, alert := range alerts.Alerts {
+		if !alert.Expires.IsZero() && alert.Expires.Before(now) {
+			continue
+		}
+
+		marked, err := s.repo.MarkAlertSent(ctx, sub.ID, alert.ID)
+		if err != nil {
+			return fmt.Errorf("failed to mark alert %s as sent: %w", alert.ID, err)
+		}
+		if !marked {
+			continue
+		}
+
+		msg := s.composer.Alert(sub, alert)
+		if err := s.emailClient.SendEmail(ctx, sub.Email, msg.Subject, msg.Body); err != nil {
+			// Forget the alert so that it is retried on the next poll
+			if unmarkErr := s.repo.UnmarkAlertSent(ctx, sub.ID, alert.ID); unmarkErr != nil {
+				logger.Error(ctx, fmt.Errorf("failed to unmark alert %s: %w", alert.ID, unmarkErr))
 			}
+			return fmt.Errorf("failed to send alert %s to %s for city %s: %w", alert.ID, sub.Email, sub.City, err)
 		}
+		logger.Info(ctx, "Weather alert sent",
+			slog.String("email", sub.Email),
+			slog.String("city", sub.City),
+			slog.String("alert_id", alert.ID))
 	}
+	return nil
 }
@@ -0,0 +1,472 @@
+package scheduler_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"Weather-API-Application/internal/client"
+	"Weather-API-Application/internal/config"
+	"Weather-API-Application/internal/model"
+	"Weather-API-Application/internal/notification"
+	"Weather-API-Application/internal/repository"
+	"Weather-API-Application/internal/services/weather_service"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeWeatherService struct {
+	weather_service.WeatherService
+	weather       *model.Weather
+	airQuality    *model.AirQuality
+	airQualityErr error
+	alerts        *model.Alerts
+	locations     []string
+}
+
+func (f *fakeWeatherService) FetchWeatherForCity(_ context.Context, city string) (*model.Weather, error) {
+	f.locations = append(f.locations, city)
+	return f.weather, nil
+}
+
+func (f *fakeWeatherService) FetchAirQuality(_ context.Context, _ string) (*model.AirQuality, error) {
+	return f.airQuality, f.airQualityErr
+}
+
+func (f *fakeWeatherService) FetchAlerts(_ context.Context, _ string) (*model.Alerts, error) {
+	return f.alerts, nil
+}
+
+type fakeRepository struct {
+	repository.SubscriptionRepository
+	subs       []*model.Subscription
+	mu         sync.Mutex
+	sent       map[string]bool
+	completed  []string
+	deliveries map[string]*fakeDelivery
+}
+
+// fakeDelivery is a row of subscription_deliveries.
+type fakeDelivery struct {
+	delivery  *model.Delivery
+	status    string
+	claimedAt time.Time
+}
+
+func deliveryKey(subId string, slot time.Time) string {
+	return subId + "|" + slot.String()
+}
+
+// deliveryStatus returns the status of the only delivery of the subscription.
+func (f *fakeRepository) deliveryStatus(subId string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, d := range f.deliveries {
+		if d.delivery.Subscription.ID == subId {
+			return d.status
+		}
+	}
+	return ""
+}
+
+func (f *fakeRepository) ListUnscheduled(_ context.Context) ([]*model.Subscription, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var subs []*model.Subscription
+	for _, sub := range f.subs {
+		if sub.Confirmed && sub.NextRunAt == nil {
+			subs = append(subs, sub)
+		}
+	}
+	return subs, nil
+}
+
+func (f *fakeRepository) ClearUnconfirmedSchedules(_ context.Context) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var cleared int64
+	for _, sub := range f.subs {
+		if !sub.Confirmed && sub.NextRunAt != nil {
+			sub.NextRunAt = nil
+			cleared++
+		}
+	}
+	return cleared, nil
+}
+
+func (f *fakeRepository) FailAbandonedDeliveries(_ context.Context, _ time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (f *fakeRepository) SetNextRun(_ context.Context, subId string, nextRun *time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, sub := range f.subs {
+		if sub.ID == subId {
+			sub.NextRunAt = nextRun
+		}
+	}
+	return nil
+}
+
+func (f *fakeRepository) ClaimDue(_ context.Context, now time.Time, limit int, lease time.Duration, next repository.NextRunFunc) ([]*model.Delivery, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var deliveries []*model.Delivery
+	for _, d := range f.deliveries {
+		stale := d.status == model.DeliveryPending || d.status == model.DeliverySending
+		if len(deliveries) < limit && stale && !d.claimedAt.After(now.Add(-lease)) && d.delivery.Attempts < 3 {
+			d.status, d.claimedAt = model.DeliveryPending, now
+			d.delivery = &model.Delivery{Subscription: d.delivery.Subscription, Slot: d.delivery.Slot, Attempts: d.delivery.Attempts + 1}
+			deliveries = append(deliveries, d.delivery)
+		}
+	}
+	for _, sub := range f.subs {
+		if len(deliveries) == limit || !sub.Confirmed || sub.NextRunAt == nil || sub.NextRunAt.After(now) {
+			continue
+		}
+		slot := *sub.NextRunAt
+		nextRun := next(sub, slot)
+		sub.NextRunAt = &nextRun
+		d := &model.Delivery{Subscription: sub, Slot: slot, Attempts: 1}
+		if sub.Frequency != model.FrequencyAlerts {
+			f.addDelivery(d, now)
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, nil
+}
+
+func (f *fakeRepository) addDelivery(d *model.Delivery, now time.Time) {
+	if f.deliveries == nil {
+		f.deliveries = map[string]*fakeDelivery{}
+	}
+	f.deliveries[deliveryKey(d.Subscription.ID, d.Slot)] = &fakeDelivery{delivery: d, status: model.DeliveryPending, claimedAt: now}
+}
+
+func (f *fakeRepository) StartDelivery(_ context.Context, subId string, slot time.Time, now time.Time) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	d, ok := f.deliveries[deliveryKey(subId, slot)]
+	if !ok || d.status != model.DeliveryPending {
+		return false, nil
+	}
+	d.status, d.claimedAt = model.DeliverySending, now
+	return true, nil
+}
+
+func (f *fakeRepository) CompleteDelivery(_ context.Context, subId string, slot time.Time, sendErr error) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.completed = append(f.completed, subId)
+	if d, ok := f.deliveries[deliveryKey(subId, slot)]; ok && (d.status == model.DeliveryPending || d.status == model.DeliverySending) {
+		d.status = model.DeliverySent
+		if sendErr != nil {
+			d.status = model.DeliveryFailed
+		}
+	}
+	return nil
+}
+
+func (f *fakeRepository) completedCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.completed)
+}
+
+func (f *fakeRepository) MarkAlertSent(_ context.Context, subId string, alertId string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.sent[subId+"|"+alertId] {
+		return false, nil
+	}
+	f.sent[subId+"|"+alertId] = true
+	return true, nil
+}
+
+func (f *fakeRepository) UnmarkAlertSent(_ context.Context, subId string, alertId string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.sent, subId+"|"+alertId)
+	return nil
+}
+
+type sentEmail struct {
+	to, subject, body, messageID string
+}
+
+type fakeEmailClient struct {
+	mu   sync.Mutex
+	sent []sentEmail
+	err  error
+	// hangs is how many sends, from the first, get no answer, like a mail server that stopped
+	// responding. They return when ctx is done.
+	hangs int
+	// attempts are the Message-IDs of every send.
+	attempts []string
+}
+
+func (f *fakeEmailClient) SendEmail(ctx context.Context, to, subject, body string) error {
+	f.mu.Lock()
+	f.attempts = append(f.attempts, client.MessageIDFrom(ctx))
+	hang := len(f.attempts) <= f.hangs
+	f.mu.Unlock()
+
+	if hang {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	f.sent = append(f.sent, sentEmail{to: to, subject: subject, body: body, messageID: client.MessageIDFrom(ctx)})
+	return nil
+}
+
+func (f *fakeEmailClient) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.sent)
+}
+
+func newTestScheduler(weatherSvc *fakeWeatherService, emailClient *fakeEmailClient) (*SchedulerService, *fakeRepository) {
+	repo := &fakeRepository{sent: map[string]bool{}}
+	cfg := &config.Config{
+		DailyStartHour:         8,
+		AlertsPollInterval:     time.Hour,
+		SchedulerWorkers:       2,
+		SchedulerPollInterval:  time.Hour,
+		SchedulerDeliveryLease: time.Minute,
+
+		SchedulerReconcileInterval: time.Hour,
+	}
+	return NewSchedulerService(repo, emailClient, weatherSvc, notification.NewComposer(), cfg), repo
+}
+
+func TestSendUpdate(t *testing.T) {
+	lat, lon := 50.45, 30.52
+	sub := &model.Subscription{
+		Email:             "user@example.com",
+		City:              "kyiv",
+		Latitude:          &lat,
+		Longitude:         &lon,
+		Location:          &model.Location{ID: "weatherapi:1", Name: "Kyiv", Lat: lat, Lon: lon},
+		IncludeAirQuality: true,
+		Units:             model.UnitsImperial,
+		Language:          "en",
+	}
+
+	tests := []struct {
+		name           string
+		airQualityErr  error
+		wantAirQuality bool
+	}{
+		{"with air quality", nil, true},
+		{"air quality unavailable", errors.New("upstream down"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			weatherSvc := &fakeWeatherService{
+				weather:       &model.Weather{Temperature: 20, Humidity: 52, Description: "Sunny"},
+				airQuality:    &model.AirQuality{USEPACategory: "Good", USEPAIndex: 1, GBDEFRAIndex: 1},
+				airQualityErr: tt.airQualityErr,
+			}
+			emailClient := &fakeEmailClient{}
+			s, repo := newTestScheduler(weatherSvc, emailClient)
+			d := &model.Delivery{Subscription: sub, Slot: time.Now(), Attempts: 1}
+			repo.addDelivery(d, time.Now())
+
+			require.NoError(t, s.sendUpdate(context.Background(), d))
+			// A slot that started sending is not sent again
+			require.ErrorIs(t, s.sendUpdate(context.Background(), d), errDeliveryTaken)
+			require.Equal(t, []string{sub.LocationQuery(), sub.LocationQuery()}, weatherSvc.locations)
+			require.Len(t, emailClient.sent, 1)
+			require.Equal(t, "Kyiv forecast", emailClient.sent[0].subject)
+			require.Contains(t, emailClient.sent[0].body, "temperature: 68.0°F")
+			require.Equal(t, tt.wantAirQuality, strings.Contains(emailClient.sent[0].body, "air quality: Good"))
+			require.Equal(t, fmt.Sprintf("update-%s-%d", sub.ID, d.Slot.Unix()), emailClient.sent[0].messageID)
+		})
+	}
+}
+
+func TestCheckAlerts(t *testing.T) {
+	now := time.Now()
+	sub := &model.Subscription{ID: "1", Email: "user@example.com", City: "Kyiv", Language: "uk"}
+	weatherSvc := &fakeWeatherService{alerts: &model.Alerts{Alerts: []model.Alert{
+		{ID: "storm", Event: "Storm", Expires: now.Add(time.Hour)},
+		{ID: "expired", Event: "Fog", Expires: now.Add(-time.Hour)},
+	}}}
+	emailClient := &fakeEmailClient{err: errors.New("smtp down")}
+	s, repo := newTestScheduler(weatherSvc, emailClient)
+
+	// A failed email is forgotten so that the next poll retries it
+	require.Error(t, s.checkAlerts(context.Background(), sub))
+	require.Empty(t, repo.sent)
+
+	emailClient.err = nil
+	require.NoError(t, s.checkAlerts(context.Background(), sub))
+	require.NoError(t, s.checkAlerts(context.Background(), sub))
+	require.Len(t, emailClient.sent, 1)
+	require.Equal(t, "Попередження про погоду для Kyiv: Storm", emailClient.sent[0].subject)
+}
+
+func TestRunTimes(t *testing.T) {
+	now := time.Date(2026, 10, 17, 9, 30, 0, 0, time.UTC)
+	tests := []struct {
+		frequency string
+		wantFirst time.Time
+		wantNext  time.Time
+	}{
+		{model.FrequencyHourly, now.Add(time.Hour), now.Add(2 * time.Hour)},
+		{model.FrequencyDaily, time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC), time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)},
+		{model.FrequencyAlerts, now, now.Add(15 * time.Minute)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.frequency, func(t *testing.T) {
+			sub := &model.Subscription{Frequency: tt.frequency}
+			first := firstRun(sub, now, 8)
+			require.Equal(t, tt.wantFirst, first)
+			require.Equal(t, tt.wantNext, nextRun(sub, first, first, 15*time.Minute))
+		})
+	}
+
+	// Runs missed while the scheduler was busy are skipped, keeping the daily hour
+	sub := &model.Subscription{Frequency: model.FrequencyDaily}
+	due := time.Date(2026, 10, 10, 8, 0, 0, 0, time.UTC)
+	require.Equal(t, time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC), nextRun(sub, due, now, 0))
+}
+
+func TestDailyRunTimeZone(t *testing.T) {
+	now := time.Date(2026, 10, 17, 9, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		location  *model.Location
+		wantFirst time.Time
+	}{
+		{name: "subscriber time zone", location: &model.Location{Timezone: "Europe/Kyiv"}, wantFirst: time.Date(2026, 10, 18, 5, 0, 0, 0, time.UTC)},
+		{name: "unresolved location", wantFirst: time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC)},
+		{name: "unknown time zone", location: &model.Location{Timezone: "Mars/Olympus_Mons"}, wantFirst: time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := &model.Subscription{Frequency: model.FrequencyDaily, Location: tt.location}
+			require.True(t, tt.wantFirst.Equal(firstRun(sub, now, 8)))
+		})
+	}
+
+	// The local hour is kept when daylight saving time ends in Kyiv on October 25
+	sub := &model.Subscription{Frequency: model.FrequencyDaily, Location: &model.Location{Timezone: "Europe/Kyiv"}}
+	due := time.Date(2026, 10, 24, 5, 0, 0, 0, time.UTC)
+	require.True(t, time.Date(2026, 10, 25, 6, 0, 0, 0, time.UTC).Equal(nextRun(sub, due, due, 0)))
+}
+
+// idleLeader is a leader election this instance never wins.
+type idleLeader struct{}
+
+func (idleLeader) Run(ctx context.Context, _ func(ctx context.Context)) {
+	<-ctx.Done()
+}
+
+func TestSchedulerDispatch(t *testing.T) {
+	weatherSvc := &fakeWeatherService{
+		weather: &model.Weather{Temperature: 20, Humidity: 52, Description: "Sunny"},
+		alerts:  &model.Alerts{Alerts: []model.Alert{{ID: "storm", Event: "Storm"}}},
+	}
+	emailClient := &fakeEmailClient{}
+	s, repo := newTestScheduler(weatherSvc, emailClient)
+
+	missed := time.Now().Add(-90 * time.Minute)
+	upcoming := time.Now().Add(30 * time.Minute)
+	repo.subs = []*model.Subscription{
+		// Confirmed without a schedule: reconciled, so the alert check is due right away
+		{ID: "1", Email: "a@example.com", City: "Kyiv", Frequency: model.FrequencyAlerts, Confirmed: true},
+		// Due while the service was down: sent once, then rescheduled on the hourly grid
+		{ID: "2", Email: "b@example.com", City: "Kyiv", Frequency: model.FrequencyHourly, Confirmed: true, NextRunAt: &missed},
+		// Not due yet
+		{ID: "3", Email: "c@example.com", City: "Kyiv", Frequency: model.FrequencyHourly, Confirmed: true, NextRunAt: &upcoming},
+		// Subscribed again on another instance and awaiting confirmation: unscheduled
+		{ID: "4", Email: "d@example.com", City: "Kyiv", Frequency: model.FrequencyHourly, NextRunAt: &missed},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, s.StartScheduler(ctx))
+	require.Error(t, s.StartScheduler(ctx))
+
+	require.Eventually(t, func() bool { return repo.completedCount() == 2 }, time.Second, 10*time.Millisecond)
+	require.Equal(t, 2, emailClient.count())
+	require.ElementsMatch(t, []string{"1", "2"}, repo.completed)
+
+	// A newly confirmed subscription wakes the dispatcher before its next poll
+	repo.mu.Lock()
+	alerts := &model.Subscription{ID: "5", Email: "e@example.com", City: "Kyiv", Frequency: model.FrequencyAlerts, Confirmed: true}
+	repo.subs = append(repo.subs, alerts)
+	repo.mu.Unlock()
+	s.StartFor(ctx, alerts)
+	require.Eventually(t, func() bool { return repo.completedCount() == 3 }, time.Second, 10*time.Millisecond)
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	require.Equal(t, missed.Add(2*time.Hour), *repo.subs[1].NextRunAt)
+	require.Equal(t, upcoming, *repo.subs[2].NextRunAt)
+	require.Nil(t, repo.subs[3].NextRunAt)
+	require.True(t, repo.subs[0].NextRunAt.After(time.Now()))
+}
+
+func TestSchedulerFollower(t *testing.T) {
+	emailClient := &fakeEmailClient{}
+	s, repo := newTestScheduler(&fakeWeatherService{alerts: &model.Alerts{}}, emailClient)
+	s.WithLeader(idleLeader{})
+
+	missed := time.Now().Add(-time.Minute)
+	repo.subs = []*model.Subscription{
+		{ID: "1", Email: "a@example.com", City: "Kyiv", Frequency: model.FrequencyAlerts, Confirmed: true},
+		{ID: "2", Email: "b@example.com", City: "Kyiv", Frequency: model.FrequencyAlerts, Confirmed: true, NextRunAt: &missed},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, s.StartScheduler(ctx))
+
+	// Instances that are not the leader still claim due runs, but leave reconciliation to the leader
+	require.Eventually(t, func() bool { return repo.completedCount() == 1 }, time.Second, 10*time.Millisecond)
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	require.Equal(t, []string{"2"}, repo.completed)
+	require.Nil(t, repo.subs[0].NextRunAt)
+}
+
+func TestSchedulerRetriesInterruptedSend(t *testing.T) {
+	weatherSvc := &fakeWeatherService{weather: &model.Weather{Temperature: 20, Humidity: 52, Description: "Sunny"}}
+	emailClient := &fakeEmailClient{hangs: 1}
+	s, repo := newTestScheduler(weatherSvc, emailClient)
+	s.cfg.SchedulerPollInterval = 10 * time.Millisecond
+	s.cfg.SchedulerDeliveryLease = 50 * time.Millisecond
+
+	due := time.Now().Add(-time.Minute)
+	repo.subs = []*model.Subscription{
+		{ID: "1", Email: "a@example.com", City: "Kyiv", Frequency: model.FrequencyDaily, Confirmed: true, NextRunAt: &due},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, s.StartScheduler(ctx))
+
+	// The first send gets no answer within the lease, so the slot is claimed again and sent once
+	require.Eventually(t, func() bool { return repo.deliveryStatus("1") == model.DeliverySent }, 2*time.Second, 10*time.Millisecond)
+	time.Sleep(4 * s.cfg.SchedulerDeliveryLease)
+	require.Equal(t, 1, emailClient.count())
+	require.Equal(t, 1, repo.completedCount())
+
+	emailClient.mu.Lock()
+	defer emailClient.mu.Unlock()
+	require.Len(t, emailClient.attempts, 2)
+	require.Equal(t, emailClient.attempts[0], emailClient.attempts[1])
+	require.NotEmpty(t, emailClient.attempts[0])
+}
@@ -7,4 +7,5 @@ var (
 	ErrNotFound                   = errors.New("subscription not found")
 	ErrAlreadyConfirmed           = errors.New("subscription already confirmed")
 	ErrFailedToCreateSubscription = errors.New("failed to create subscription")
+	ErrLocationNotFound           = errors.New("location not found")
 )
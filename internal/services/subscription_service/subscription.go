@@ -5,15 +5,17 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
-	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 
 	"Weather-API-Application/internal/client"
 	"Weather-API-Application/internal/config"
+	"Weather-API-Application/internal/i18n"
 	"Weather-API-Application/internal/logger"
 	"Weather-API-Application/internal/model"
+	"Weather-API-Application/internal/provider"
 	"Weather-API-Application/internal/repository"
 )
 
@@ -22,11 +24,17 @@ type Scheduler interface {
 	StopFor(sub *model.Subscription)
 }
 
+// LocationResolver resolves free-text city names to canonical locations.
+type LocationResolver interface {
+	ResolveLocation(ctx context.Context, query string) (*model.Location, error)
+}
+
 type SubscriptionService struct {
 	repo        repository.SubscriptionRepository
 	emailClient client.Client
 	cfg         *config.Config
 	scheduler   Scheduler
+	resolver    LocationResolver
 	mu          sync.Mutex
 }
 
@@ -43,8 +51,33 @@ func (s *SubscriptionService) WithScheduler(scheduler Scheduler) *SubscriptionSe
 	return s
 }
 
+// WithLocationResolver enables resolving subscription cities to canonical locations.
+func (s *SubscriptionService) WithLocationResolver(resolver LocationResolver) *SubscriptionService {
+	s.resolver = resolver
+	return s
+}
+
 // Subscribe creates a new subscription or updates a pending one and sends a confirmation email.
+//
+// The city is resolved to a canonical location first, so different spellings of the same place
+// are one subscription. Unknown cities are rejected with ErrLocationNotFound; if the resolver is
+// unavailable the subscription is stored unresolved and picked up by RunLocationBackfill later.
 func (s *SubscriptionService) Subscribe(ctx context.Context, req *model.Subscription) error {
+	req.Location = nil
+	if s.resolver != nil {
+		location, err := s.resolver.ResolveLocation(ctx, req.LocationQuery())
+		switch {
+		case errors.Is(err, provider.ErrLocationNotFound):
+			return ErrLocationNotFound
+		case err != nil:
+			logger.Error(ctx, fmt.Errorf("failed to resolve location, storing it unresolved: %w", err),
+				slog.String("email", req.Email),
+				slog.String("city", req.City))
+		default:
+			req.SetLocation(location)
+		}
+	}
+
 	rowExists, confirmed, err := s.repo.CheckConfirmation(ctx, req)
 	if err != nil {
 		return fmt.Errorf("check confirmation: %w", err)
@@ -54,18 +87,28 @@ func (s *SubscriptionService) Subscribe(ctx context.Context, req *model.Subscrip
 	if !rowExists {
 		token := createNewToken()
 		sub := &model.Subscription{
-			Email:     req.Email,
-			City:      req.City,
-			Frequency: req.Frequency,
-			Token:     token,
-			Confirmed: false,
+			Email:             req.Email,
+			City:              req.City,
+			Latitude:          req.Latitude,
+			Longitude:         req.Longitude,
+			Location:          req.Location,
+			Frequency:         req.Frequency,
+			IncludeAirQuality: req.IncludeAirQuality,
+			Units:             req.Units,
+			Language:          req.Language,
+			Token:             token,
+			Confirmed:         false,
 		}
 
 		if err := s.repo.Create(ctx, sub); err != nil {
+			if errors.Is(err, repository.ErrDuplicate) {
+				return ErrSubscriptionExists
+			}
 			return ErrFailedToCreateSubscription
 		}
 
-		if err := s.emailClient.SendEmail(ctx, sub.Email, config.ConfirmSubject, config.BuildConfirmBody(s.cfg.BaseURL, token)); err != nil {
+		lang, _ := i18n.Parse(sub.Language)
+		if err := s.emailClient.SendEmail(ctx, sub.Email, config.ConfirmSubject(lang), config.BuildConfirmBody(s.cfg.BaseURL, token, lang)); err != nil {
 			logger.Error(ctx, err,
 				slog.String("email", sub.Email),
 				slog.String("city", sub.City))
@@ -85,7 +128,8 @@ func (s *SubscriptionService) Subscribe(ctx context.Context, req *model.Subscrip
 			return fmt.Errorf("failed to update subscription token: %w", err)
 		}
 
-		if err := s.emailClient.SendEmail(ctx, req.Email, config.ConfirmSubject, config.BuildConfirmBody(s.cfg.BaseURL, token)); err != nil {
+		lang, _ := i18n.Parse(req.Language)
+		if err := s.emailClient.SendEmail(ctx, req.Email, config.ConfirmSubject(lang), config.BuildConfirmBody(s.cfg.BaseURL, token, lang)); err != nil {
 			logger.Error(ctx, err,
 				slog.String("email", req.Email),
 				slog.String("city", req.City))
@@ -105,7 +149,7 @@ func (s *SubscriptionService) Subscribe(ctx context.Context, req *model.Subscrip
 func (s *SubscriptionService) ConfirmSubscription(ctx context.Context, token string) (*model.Subscription, error) {
 	subId, sub, err := s.repo.GetByToken(ctx, token)
 	if err != nil {
-		if errors.Is(err, ErrNotFound) {
+		if errors.Is(err, repository.ErrNotFound) {
 			return nil, ErrNotFound
 		}
 		return nil, fmt.Errorf("failed to scan subscription: %w", err)
@@ -133,7 +177,7 @@ func (s *SubscriptionService) ConfirmSubscription(ctx context.Context, token str
 func (s *SubscriptionService) Unsubscribe(ctx context.Context, token string) error {
 	_, sub, err := s.repo.GetByToken(ctx, token)
 	if err != nil {
-		if errors.Is(err, ErrNotFound) {
+		if errors.Is(err, repository.ErrNotFound) {
 			return ErrNotFound
 		}
 		return fmt.Errorf("failed to scan subscription: %w", err)
@@ -153,12 +197,84 @@ func (s *SubscriptionService) Unsubscribe(ctx context.Context, token string) err
 	return nil
 }
 
-func (s *SubscriptionService) fetchConfirmedSubscriptions(ctx context.Context) ([]*model.Subscription, error) {
-	return s.repo.ListConfirmed(ctx)
+// maxLocationAttempts is how many times the backfill tries to resolve a subscription's location.
+const maxLocationAttempts = 5
+
+// RunLocationBackfill backfills locations right away and then every LocationBackfillInterval
+// until ctx is cancelled.
+func (s *SubscriptionService) RunLocationBackfill(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.LocationBackfillInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.BackfillLocations(ctx); err != nil && ctx.Err() == nil {
+			logger.Error(ctx, fmt.Errorf("failed to backfill subscription locations: %w", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// BackfillLocations resolves the canonical location of up to LocationBackfillBatchSize
+// subscriptions stored without one. Cities that cannot be resolved, and duplicates of an
+// already resolved subscription, are logged and counted as failed attempts; subscriptions
+// are skipped once they failed maxLocationAttempts times.
+func (s *SubscriptionService) BackfillLocations(ctx context.Context) error {
+	if s.resolver == nil {
+		return nil
+	}
+
+	subs, err := s.repo.ListUnresolved(ctx, maxLocationAttempts, s.cfg.LocationBackfillBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to fetch unresolved subscriptions: %w", err)
+	}
+	if len(subs) == 0 {
+		return nil
+	}
+
+	resolved := 0
+	for _, sub := range subs {
+		location, err := s.resolver.ResolveLocation(ctx, sub.LocationQuery())
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			logger.Error(ctx, fmt.Errorf("failed to resolve location: %w", err),
+				slog.String("email", sub.Email),
+				slog.String("city", sub.City))
+			if err := s.repo.RecordLocationFailure(ctx, sub.ID); err != nil {
+				return fmt.Errorf("failed to record location failure: %w", err)
+			}
+			continue
+		}
+		if err := s.repo.SetLocation(ctx, sub.ID, location); err != nil {
+			if !errors.Is(err, repository.ErrDuplicate) {
+				return fmt.Errorf("failed to store location: %w", err)
+			}
+			logger.Info(ctx, "Subscription duplicates an existing one, leaving it unresolved",
+				slog.String("email", sub.Email),
+				slog.String("city", sub.City),
+				slog.String("location_id", location.ID))
+			if err := s.repo.RecordLocationFailure(ctx, sub.ID); err != nil {
+				return fmt.Errorf("failed to record location failure: %w", err)
+			}
+			continue
+		}
+		resolved++
+	}
+
+	logger.Info(ctx, "Subscription locations backfilled",
+		slog.Int("resolved", resolved),
+		slog.Int("unresolved", len(subs)-resolved))
+	return nil
 }
 
 func MakeKey(sub *model.Subscription) string {
-	return fmt.Sprintf("%s|%s", sub.Email, strings.ToLower(sub.City))
+	return sub.Key()
 }
 
 func createNewToken() string {
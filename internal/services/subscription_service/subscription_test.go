@@ -0,0 +1,170 @@
+package subscription_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"Weather-API-Application/internal/config"
+	"Weather-API-Application/internal/model"
+	"Weather-API-Application/internal/provider"
+	"Weather-API-Application/internal/repository"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeResolver struct {
+	locations map[string]*model.Location
+	queries   []string
+}
+
+func (f *fakeResolver) ResolveLocation(_ context.Context, query string) (*model.Location, error) {
+	f.queries = append(f.queries, query)
+	if location, ok := f.locations[query]; ok {
+		return location, nil
+	}
+	if query == "Offline" {
+		return nil, errors.New("provider unavailable")
+	}
+	return nil, provider.ErrLocationNotFound
+}
+
+type fakeRepository struct {
+	repository.SubscriptionRepository
+	subs     []*model.Subscription
+	attempts map[string]int
+	// taken are location IDs the subscriptions' email is already subscribed to
+	taken map[string]bool
+}
+
+func (f *fakeRepository) ListUnresolved(_ context.Context, maxAttempts, limit int) ([]*model.Subscription, error) {
+	var subs []*model.Subscription
+	for _, sub := range f.subs {
+		if sub.Location == nil && f.attempts[sub.ID] < maxAttempts && len(subs) < limit {
+			subs = append(subs, sub)
+		}
+	}
+	return subs, nil
+}
+
+func (f *fakeRepository) SetLocation(_ context.Context, subId string, location *model.Location) error {
+	if f.taken[location.ID] {
+		return repository.ErrDuplicate
+	}
+	for _, sub := range f.subs {
+		if sub.ID == subId {
+			sub.SetLocation(location)
+		}
+	}
+	return nil
+}
+
+func (f *fakeRepository) GetByToken(_ context.Context, token string) (string, *model.Subscription, error) {
+	for _, sub := range f.subs {
+		if sub.Token == token {
+			return sub.ID, sub, nil
+		}
+	}
+	return "", nil, repository.ErrNotFound
+}
+
+func (f *fakeRepository) RecordLocationFailure(_ context.Context, subId string) error {
+	f.attempts[subId]++
+	return nil
+}
+
+func newBackfillService(subs []*model.Subscription, batchSize int) (*SubscriptionService, *fakeRepository, *fakeResolver) {
+	repo := &fakeRepository{
+		subs:     subs,
+		attempts: map[string]int{},
+		taken:    map[string]bool{"test:lviv": true},
+	}
+	resolver := &fakeResolver{locations: map[string]*model.Location{
+		"Kyiv":        {ID: "test:kyiv", Name: "Kyiv", Lat: 50.45, Lon: 30.52},
+		"50.44,30.51": {ID: "test:kyiv", Name: "Kyiv", Lat: 50.45, Lon: 30.52},
+		"Lviv":        {ID: "test:lviv", Name: "Lviv"},
+	}}
+	svc := NewSubscriptionService(repo, nil, &config.Config{LocationBackfillBatchSize: batchSize}).
+		WithLocationResolver(resolver)
+	return svc, repo, resolver
+}
+
+func TestBackfillLocations(t *testing.T) {
+	tests := []struct {
+		name         string
+		city         string
+		wantLocation string
+		wantAttempts int
+	}{
+		{name: "resolved", city: "Kyiv", wantLocation: "test:kyiv"},
+		{name: "unknown city", city: "Atlantis", wantAttempts: 1},
+		{name: "resolver unavailable", city: "Offline", wantAttempts: 1},
+		{name: "duplicate location", city: "Lviv", wantAttempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := &model.Subscription{ID: "1", Email: "user@example.com", City: tt.city}
+			svc, repo, _ := newBackfillService([]*model.Subscription{sub}, 10)
+
+			require.NoError(t, svc.BackfillLocations(context.Background()))
+			if tt.wantLocation != "" {
+				require.NotNil(t, sub.Location)
+				require.Equal(t, tt.wantLocation, sub.Location.ID)
+			} else {
+				require.Nil(t, sub.Location)
+			}
+			require.Equal(t, tt.wantAttempts, repo.attempts[sub.ID])
+		})
+	}
+}
+
+func TestBackfillLocationsKeepsCoordinates(t *testing.T) {
+	lat, lon := 50.44, 30.51
+	withCoordinates := &model.Subscription{ID: "1", City: "Kyiv", Latitude: &lat, Longitude: &lon}
+	byCity := &model.Subscription{ID: "2", City: "Kyiv"}
+	svc, _, _ := newBackfillService([]*model.Subscription{withCoordinates, byCity}, 10)
+
+	require.NoError(t, svc.BackfillLocations(context.Background()))
+	// Coordinates given by the subscriber are kept, the others come from the location
+	require.Equal(t, "test:kyiv", withCoordinates.Location.ID)
+	require.Equal(t, 50.44, *withCoordinates.Latitude)
+	require.Equal(t, 30.51, *withCoordinates.Longitude)
+	require.Equal(t, 50.45, *byCity.Latitude)
+	require.Equal(t, 30.52, *byCity.Longitude)
+}
+
+func TestBackfillLocationsLimits(t *testing.T) {
+	subs := []*model.Subscription{
+		{ID: "1", City: "Atlantis"},
+		{ID: "2", City: "Kyiv"},
+		{ID: "3", City: "Kyiv"},
+	}
+	svc, repo, resolver := newBackfillService(subs, 2)
+	ctx := context.Background()
+
+	// Each pass is capped at the batch size
+	require.NoError(t, svc.BackfillLocations(ctx))
+	require.Equal(t, []string{"Atlantis", "Kyiv"}, resolver.queries)
+	require.Nil(t, subs[2].Location)
+
+	require.NoError(t, svc.BackfillLocations(ctx))
+	require.NotNil(t, subs[2].Location)
+
+	// The failing subscription is skipped once it used up its attempts
+	for range maxLocationAttempts {
+		require.NoError(t, svc.BackfillLocations(ctx))
+	}
+	require.Equal(t, maxLocationAttempts, repo.attempts["1"])
+	resolver.queries = nil
+	require.NoError(t, svc.BackfillLocations(ctx))
+	require.Empty(t, resolver.queries)
+}
+
+func TestUnknownToken(t *testing.T) {
+	svc, _, _ := newBackfillService(nil, 10)
+
+	_, err := svc.ConfirmSubscription(context.Background(), "unknown")
+	require.ErrorIs(t, err, ErrNotFound)
+	require.ErrorIs(t, svc.Unsubscribe(context.Background(), "unknown"), ErrNotFound)
+}
@@ -0,0 +1,112 @@
+package weather_service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"Weather-API-Application/internal/config"
+	"Weather-API-Application/internal/model"
+	"Weather-API-Application/internal/provider"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProvider answers CurrentWeather after delay, failing for the locations in errs,
+// and tracks how many calls run at once.
+type fakeProvider struct {
+	provider.WeatherProvider
+	errs  map[string]error
+	delay time.Duration
+
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+}
+
+func (p *fakeProvider) Name() string {
+	return "fake"
+}
+
+func (p *fakeProvider) CurrentWeather(ctx context.Context, location string) (*model.Weather, error) {
+	p.mu.Lock()
+	p.inFlight++
+	p.maxInFlight = max(p.maxInFlight, p.inFlight)
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		p.inFlight--
+		p.mu.Unlock()
+	}()
+
+	time.Sleep(p.delay)
+	if err := p.errs[location]; err != nil {
+		return nil, err
+	}
+	return &model.Weather{Description: location}, nil
+}
+
+func TestFetchWeatherForCities(t *testing.T) {
+	wp := &fakeProvider{
+		errs: map[string]error{
+			"Atlantis": provider.ErrLocationNotFound,
+			"Lviv":     provider.ErrQuotaExceeded,
+		},
+		delay: time.Millisecond,
+	}
+	svc := NewService(&config.Config{WeatherBatchConcurrency: 2}, wp)
+
+	cities := []string{"Kyiv", "Atlantis", " ", "Lviv", "Odesa", "Dnipro"}
+	results := svc.FetchWeatherForCities(context.Background(), cities)
+
+	wantErrs := []error{nil, ErrCityNotFound, ErrInvalidInput, ErrQuotaExceeded, nil, nil}
+	require.Len(t, results, len(cities))
+	for i, res := range results {
+		if wantErrs[i] != nil {
+			require.ErrorIs(t, res.Err, wantErrs[i], cities[i])
+			require.Nil(t, res.Weather, cities[i])
+			continue
+		}
+		require.NoError(t, res.Err, cities[i])
+		require.Equal(t, cities[i], res.Weather.Description)
+	}
+}
+
+func TestFetchWeatherForCitiesConcurrency(t *testing.T) {
+	tests := []struct {
+		name         string
+		concurrency  int
+		cities       int
+		wantInFlight int
+	}{
+		{name: "bounded by concurrency", concurrency: 3, cities: 12, wantInFlight: 3},
+		{name: "bounded by cities", concurrency: 8, cities: 2, wantInFlight: 2},
+		{name: "sequential", concurrency: 1, cities: 4, wantInFlight: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wp := &fakeProvider{delay: 20 * time.Millisecond}
+			svc := NewService(&config.Config{WeatherBatchConcurrency: tt.concurrency}, wp)
+
+			cities := make([]string, tt.cities)
+			for i := range cities {
+				cities[i] = "City"
+			}
+			results := svc.FetchWeatherForCities(context.Background(), cities)
+			require.Len(t, results, tt.cities)
+			require.Equal(t, tt.wantInFlight, wp.maxInFlight)
+		})
+	}
+}
+
+func TestFetchWeatherForCitiesCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	svc := NewService(&config.Config{WeatherBatchConcurrency: 2}, &fakeProvider{})
+
+	for _, res := range svc.FetchWeatherForCities(ctx, []string{"Kyiv", "Lviv"}) {
+		require.ErrorIs(t, res.Err, context.Canceled)
+	}
+}
@@ -0,0 +1,15 @@
+package weather_service
+
+import "errors"
+
+// Errors returned by the weather service. Provider errors are wrapped in one of them,
+// so both the domain error and the underlying cause can be matched with errors.Is and errors.As.
+var (
+	ErrInvalidInput        = errors.New("invalid input")
+	ErrCityNotFound        = errors.New("city not found")
+	ErrNoData              = errors.New("no weather data for the requested period")
+	ErrNotSupported        = errors.New("not supported by the weather provider")
+	ErrQuotaExceeded       = errors.New("weather provider quota exceeded")
+	ErrUpstreamUnavailable = errors.New("weather provider unavailable")
+	ErrCircuitOpen         = errors.New("weather provider temporarily disabled after repeated failures")
+)
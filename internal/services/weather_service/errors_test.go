@@ -0,0 +1,52 @@
+package weather_service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"Weather-API-Application/internal/config"
+	"Weather-API-Application/internal/provider"
+	"Weather-API-Application/internal/resilience"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapProviderError(t *testing.T) {
+	upstreamErr := &provider.UpstreamError{Provider: "fake", StatusCode: http.StatusBadGateway}
+	openErr := &resilience.OpenError{Name: "fake", RetryAfter: time.Minute}
+
+	tests := []struct {
+		name      string
+		err       error
+		cancelled bool
+		wantErr   error
+	}{
+		{name: "location not found", err: provider.ErrLocationNotFound, wantErr: ErrCityNotFound},
+		{name: "no data", err: provider.ErrNoData, wantErr: ErrNoData},
+		{name: "not supported", err: provider.ErrNotSupported, wantErr: ErrNotSupported},
+		{name: "quota exceeded", err: provider.ErrQuotaExceeded, wantErr: ErrQuotaExceeded},
+		{name: "circuit open", err: openErr, wantErr: ErrCircuitOpen},
+		{name: "upstream error", err: upstreamErr, wantErr: ErrUpstreamUnavailable},
+		{name: "unknown error", err: errors.New("connection reset"), wantErr: ErrUpstreamUnavailable},
+		{name: "cancelled request", err: upstreamErr, cancelled: true, wantErr: context.Canceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancelled {
+				cancel()
+			}
+			svc := NewService(&config.Config{}, &fakeProvider{errs: map[string]error{"Kyiv": tt.err}})
+
+			_, err := svc.FetchWeatherForCity(ctx, "Kyiv")
+			require.ErrorIs(t, err, tt.wantErr)
+			// The provider error stays matchable behind the service error
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
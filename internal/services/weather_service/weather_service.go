@@ -1,64 +1,375 @@
 package weather_service
 
 import (
+	"Weather-API-Application/internal/cache"
 	"Weather-API-Application/internal/config"
+	"Weather-API-Application/internal/logger"
 	"Weather-API-Application/internal/model"
-	"encoding/json"
+	"Weather-API-Application/internal/provider"
+	"Weather-API-Application/internal/repository"
+	"Weather-API-Application/internal/resilience"
+	"Weather-API-Application/internal/utils/astronomy"
+	"Weather-API-Application/internal/utils/validate"
+	"context"
+	"errors"
 	"fmt"
-	"net/http"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
 )
 
+// minLocalObservations is the number of stored observations for a day
+// (roughly one every other hour) required to answer history requests locally.
+const minLocalObservations = 12
+
 // WeatherService defines the interface for weather operations
 type WeatherService interface {
-	FetchWeatherForCity(city string) (*model.Weather, error, int)
+	FetchWeatherForCity(ctx context.Context, city string) (*model.Weather, error)
+	FetchWeatherForCities(ctx context.Context, cities []string) []WeatherResult
+	FetchForecast(ctx context.Context, city string, days int) (*model.Forecast, error)
+	FetchHourlyForecast(ctx context.Context, city string, from, to time.Time) (*model.HourlyForecast, error)
+	FetchHistory(ctx context.Context, city string, date time.Time) (*model.History, error)
+	FetchAirQuality(ctx context.Context, city string) (*model.AirQuality, error)
+	FetchAlerts(ctx context.Context, city string) (*model.Alerts, error)
+	FetchAstronomy(ctx context.Context, city string, date time.Time) (*model.Astronomy, error)
+	SearchCities(ctx context.Context, query string) ([]model.Location, error)
+}
+
+// WeatherResult is the outcome of fetching the weather for one city of a batch.
+type WeatherResult struct {
+	Weather *model.Weather
+	Err     error
 }
 
 type Service struct {
-	cfg *config.Config
+	cfg          *config.Config
+	provider     provider.WeatherProvider
+	observations repository.ObservationRepository
 }
 
-func NewService(cfg *config.Config) *Service {
+func NewService(cfg *config.Config, weatherProvider provider.WeatherProvider) *Service {
 	return &Service{
-		cfg: cfg,
+		cfg:      cfg,
+		provider: weatherProvider,
 	}
 }
 
+// WithObservations enables recording of fetched weather and answering history requests from it.
+func (s *Service) WithObservations(observations repository.ObservationRepository) *Service {
+	s.observations = observations
+	return s
+}
+
 // FetchWeatherForCity retrieves the current weather data for the given city
-// using the external WeatherAPI.com services.
+// using the configured weather provider.
 //
 // It performs the following steps:
-//   - Validates that the API key is present in the config.
-//   - Sends an HTTP GET request to the Weather API with the city query.
-//   - Returns 400 if the request fails to reach the API,
-//     404 if the city is not found,
-//     or 500 if decoding the response fails.
-//   - On success, returns a populated Weather struct with temperature, humidity, and description.
-func (s *Service) FetchWeatherForCity(city string) (*model.Weather, error, int) {
+//   - Requests the current weather for the city from the provider.
+//   - Returns ErrCityNotFound if the provider does not know the city, ErrQuotaExceeded
+//     if the provider quota is used up, or ErrUpstreamUnavailable if the provider is
+//     unreachable or responds with an error.
+//   - On success, records the observation (when fresh from upstream) and returns
+//     a populated Weather struct.
+func (s *Service) FetchWeatherForCity(ctx context.Context, city string) (*model.Weather, error) {
+	if err := validateCity(city); err != nil {
+		return nil, err
+	}
+
+	weather, err := s.provider.CurrentWeather(ctx, city)
+	if err != nil {
+		return nil, s.mapProviderError(ctx, err)
+	}
+
+	if weather.Cache == nil || weather.Cache.Status == string(cache.StatusMiss) {
+		s.recordObservation(ctx, city, weather)
+	}
+
+	return weather, nil
+}
+
+// FetchWeatherForCities retrieves the current weather for several cities concurrently,
+// with at most WeatherBatchConcurrency upstream lookups at a time. Results are in the
+// order of cities and carry the same errors as FetchWeatherForCity. Cities not started
+// when ctx is cancelled get the context error.
+func (s *Service) FetchWeatherForCities(ctx context.Context, cities []string) []WeatherResult {
+	results := make([]WeatherResult, len(cities))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for range min(s.cfg.WeatherBatchConcurrency, len(cities)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				weather, err := s.FetchWeatherForCity(ctx, cities[i])
+				results[i] = WeatherResult{Weather: weather, Err: err}
+			}
+		}()
+	}
+
+	for i := range cities {
+		if ctx.Err() != nil {
+			results[i] = WeatherResult{Err: ctx.Err()}
+			continue
+		}
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
+
+// FetchForecast retrieves a daily forecast for the given city and number of days,
+// with the same errors as FetchWeatherForCity.
+func (s *Service) FetchForecast(ctx context.Context, city string, days int) (*model.Forecast, error) {
+	if err := validateCity(city); err != nil {
+		return nil, err
+	}
+	if !validate.IsValidForecastDays(days) {
+		return nil, fmt.Errorf("%w: days must be between 1 and %d", ErrInvalidInput, validate.MaxForecastDays)
+	}
 
-	if s.cfg.WeatherApiKey == "" {
-		return nil, fmt.Errorf("weather API key is missing in config"), http.StatusInternalServerError
+	forecast, err := s.provider.Forecast(ctx, city, days)
+	if err != nil {
+		return nil, s.mapProviderError(ctx, err)
 	}
 
-	url := fmt.Sprintf("https://api.weatherapi.com/v1/current.json?key=%s&q=%s&aqi=no", s.cfg.WeatherApiKey, city)
+	return forecast, nil
+}
+
+// FetchHourlyForecast retrieves hourly forecast points for the given city between from and to,
+// with the same errors as FetchWeatherForCity.
+func (s *Service) FetchHourlyForecast(ctx context.Context, city string, from, to time.Time) (*model.HourlyForecast, error) {
+	if err := validateCity(city); err != nil {
+		return nil, err
+	}
+	if !validate.IsValidHourlyWindow(from, to) {
+		return nil, fmt.Errorf("%w: invalid time window %s - %s", ErrInvalidInput, from, to)
+	}
 
-	resp, err := http.Get(url)
+	forecast, err := s.provider.HourlyForecast(ctx, city, from, to)
 	if err != nil {
-		return nil, fmt.Errorf("invalid request: failed to fetch weather data: %w", err), http.StatusBadRequest
+		return nil, s.mapProviderError(ctx, err)
+	}
+
+	return forecast, nil
+}
+
+// FetchHistory retrieves the observed weather for the given city on a past date (UTC).
+//
+// Locally stored observations are used when there are enough of them for that day,
+// otherwise the provider's history API is queried. If the provider fails, whatever
+// local observations exist are returned instead. Returns ErrNoData if no data is available.
+func (s *Service) FetchHistory(ctx context.Context, city string, date time.Time) (*model.History, error) {
+	if err := validateCity(city); err != nil {
+		return nil, err
+	}
+	if !validate.IsValidHistoryDate(date, time.Now()) {
+		return nil, fmt.Errorf("%w: %s is not a past date", ErrInvalidInput, date.Format(time.DateOnly))
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("city not found: failed to fetch weather data: %s", resp.Status), http.StatusNotFound
+	var local []*model.Observation
+	if s.observations != nil {
+		var err error
+		local, err = s.observations.ListByCity(ctx, observationKey(city), date, date.Add(24*time.Hour))
+		if err != nil {
+			logger.Error(ctx, fmt.Errorf("failed to load observations: %w", err), slog.String("city", city))
+		}
+		if len(local) >= minLocalObservations {
+			return summarizeObservations(city, date, local), nil
+		}
 	}
 
-	var weatherApiResp model.WeatherAPIResponse
-	if err = json.NewDecoder(resp.Body).Decode(&weatherApiResp); err != nil {
-		return nil, fmt.Errorf("failed to decode weather data: %w", err), http.StatusInternalServerError
+	history, err := s.provider.History(ctx, city, date)
+	if err != nil {
+		if len(local) > 0 && ctx.Err() == nil {
+			logger.Error(ctx, fmt.Errorf("history provider failed, using local observations: %w", err), slog.String("city", city))
+			return summarizeObservations(city, date, local), nil
+		}
+		return nil, s.mapProviderError(ctx, err)
+	}
+
+	return history, nil
+}
+
+// FetchAirQuality retrieves current air quality for the given city,
+// with the same errors as FetchWeatherForCity.
+func (s *Service) FetchAirQuality(ctx context.Context, city string) (*model.AirQuality, error) {
+	if err := validateCity(city); err != nil {
+		return nil, err
+	}
+
+	airQuality, err := s.provider.AirQuality(ctx, city)
+	if err != nil {
+		return nil, s.mapProviderError(ctx, err)
+	}
+
+	return airQuality, nil
+}
+
+// FetchAlerts retrieves active severe weather alerts for the given city,
+// with the same errors as FetchWeatherForCity, or ErrNotSupported.
+func (s *Service) FetchAlerts(ctx context.Context, city string) (*model.Alerts, error) {
+	if err := validateCity(city); err != nil {
+		return nil, err
 	}
 
-	return &model.Weather{
-		Temperature: weatherApiResp.Current.TempC,
-		Humidity:    weatherApiResp.Current.Humidity,
-		Description: weatherApiResp.Current.Condition.Text,
-	}, nil, http.StatusOK
+	alerts, err := s.provider.Alerts(ctx, city)
+	if err != nil {
+		return nil, s.mapProviderError(ctx, err)
+	}
+
+	return alerts, nil
+}
+
+// FetchAstronomy retrieves sunrise, sunset and moon data for the given city and date,
+// with the same errors as FetchAlerts.
+//
+// If the provider fails and the city is a "lat,lon" pair, the sun times and moon phase
+// are calculated locally instead, so the endpoint keeps working without the upstream.
+func (s *Service) FetchAstronomy(ctx context.Context, city string, date time.Time) (*model.Astronomy, error) {
+	if err := validateCity(city); err != nil {
+		return nil, err
+	}
+	if !validate.IsValidAstronomyDate(date, time.Now()) {
+		return nil, fmt.Errorf("%w: %s is more than a year from today", ErrInvalidInput, date.Format(time.DateOnly))
+	}
+
+	astro, err := s.provider.Astronomy(ctx, city, date)
+	if err != nil {
+		if coords, ok := model.ParseCoordinates(city); ok && ctx.Err() == nil {
+			logger.Error(ctx, fmt.Errorf("astronomy provider failed, calculating locally: %w", err), slog.String("city", city))
+			return calculateAstronomy(city, coords, date), nil
+		}
+		return nil, s.mapProviderError(ctx, err)
+	}
+
+	return astro, nil
+}
+
+// SearchCities returns locations matching a partial city name. No matches is not an error
+// and yields an empty list; other failures are the same as for FetchWeatherForCity.
+func (s *Service) SearchCities(ctx context.Context, query string) ([]model.Location, error) {
+	if !validate.IsValidSearchQuery(query) {
+		return nil, fmt.Errorf("%w: query must be %d to %d characters long",
+			ErrInvalidInput, validate.MinSearchQueryLength, validate.MaxSearchQueryLength)
+	}
+
+	locations, err := s.provider.SearchLocations(ctx, query)
+	if errors.Is(err, provider.ErrLocationNotFound) {
+		return []model.Location{}, nil
+	}
+	if err != nil {
+		return nil, s.mapProviderError(ctx, err)
+	}
+
+	return locations, nil
+}
+
+// mapProviderError wraps a provider error in the matching service error. Errors caused by
+// ctx being cancelled or timing out are returned as the context error.
+func (s *Service) mapProviderError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return fmt.Errorf("%w: %w", ctxErr, err)
+	}
+	switch {
+	case errors.Is(err, provider.ErrLocationNotFound):
+		return fmt.Errorf("%w: %w", ErrCityNotFound, err)
+	case errors.Is(err, provider.ErrNoData):
+		return fmt.Errorf("%w: %w", ErrNoData, err)
+	case errors.Is(err, provider.ErrNotSupported):
+		return fmt.Errorf("%w: %s: %w", ErrNotSupported, s.provider.Name(), err)
+	case errors.Is(err, provider.ErrQuotaExceeded):
+		return fmt.Errorf("%w: %s: %w", ErrQuotaExceeded, s.provider.Name(), err)
+	case errors.Is(err, resilience.ErrOpen):
+		return fmt.Errorf("%w: %w", ErrCircuitOpen, err)
+	}
+	return fmt.Errorf("%w: failed to fetch weather data from %s: %w", ErrUpstreamUnavailable, s.provider.Name(), err)
+}
+
+// validateCity returns ErrInvalidInput for a blank city.
+func validateCity(city string) error {
+	if !validate.IsValidCity(city) {
+		return fmt.Errorf("%w: city is required", ErrInvalidInput)
+	}
+	return nil
+}
+
+// recordObservation stores the weather snapshot; failures are logged and otherwise ignored.
+func (s *Service) recordObservation(ctx context.Context, city string, weather *model.Weather) {
+	if s.observations == nil {
+		return
+	}
+	err := s.observations.Save(ctx, &model.Observation{
+		City:        observationKey(city),
+		ObservedAt:  time.Now().UTC(),
+		Temperature: weather.Temperature,
+		Humidity:    weather.Humidity,
+		Description: weather.Description,
+	})
+	if err != nil {
+		logger.Error(ctx, fmt.Errorf("failed to record observation: %w", err), slog.String("city", city))
+	}
+}
+
+// summarizeObservations builds a daily history from locally stored observations.
+func summarizeObservations(city string, date time.Time, observations []*model.Observation) *model.History {
+	history := &model.History{
+		City:   city,
+		Date:   date.Format(time.DateOnly),
+		Source: model.HistorySourceLocal,
+		Hours:  make([]model.HistoryHour, 0, len(observations)),
+	}
+
+	conditions := map[string]int{}
+	var temperatureSum, humiditySum float64
+	for i, o := range observations {
+		if i == 0 || o.Temperature < history.MinTemperature {
+			history.MinTemperature = o.Temperature
+		}
+		if i == 0 || o.Temperature > history.MaxTemperature {
+			history.MaxTemperature = o.Temperature
+		}
+		temperatureSum += o.Temperature
+		humiditySum += o.Humidity
+
+		conditions[o.Description]++
+		if conditions[o.Description] > conditions[history.Condition] {
+			history.Condition = o.Description
+		}
+
+		history.Hours = append(history.Hours, model.HistoryHour{
+			Time:        o.ObservedAt.UTC(),
+			Temperature: o.Temperature,
+			Humidity:    o.Humidity,
+			Condition:   o.Description,
+		})
+	}
+
+	history.AvgTemperature = temperatureSum / float64(len(observations))
+	history.AvgHumidity = humiditySum / float64(len(observations))
+	return history
+}
+
+// calculateAstronomy computes sun times (UTC) and the moon phase for the coordinates.
+// Moonrise and moonset are not calculated.
+func calculateAstronomy(city string, coords model.Coordinates, date time.Time) *model.Astronomy {
+	sunrise, sunset := astronomy.SunTimes(date, coords.Lat, coords.Lon)
+	phase, illumination := astronomy.MoonPhase(time.Date(date.Year(), date.Month(), date.Day(), 12, 0, 0, 0, time.UTC))
+	return &model.Astronomy{
+		City:             city,
+		Date:             date.Format(time.DateOnly),
+		Source:           model.AstronomySourceCalculated,
+		Sunrise:          sunrise,
+		Sunset:           sunset,
+		MoonPhase:        phase,
+		MoonIllumination: illumination,
+	}
+}
+
+// observationKey normalizes a city name for storing and looking up observations.
+func observationKey(city string) string {
+	return strings.ToLower(strings.TrimSpace(city))
 }
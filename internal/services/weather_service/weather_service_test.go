@@ -0,0 +1,84 @@
+package weather_service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"Weather-API-Application/internal/config"
+	"Weather-API-Application/internal/fakeweather"
+	"Weather-API-Application/internal/infrastructure/provider"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestFetchWeatherForCityErrors runs the WeatherAPI.com provider against the fake server
+// and checks how upstream responses are mapped to service errors.
+func TestFetchWeatherForCityErrors(t *testing.T) {
+	kyiv := fakeweather.DefaultCities()[0]
+	city := func(name string, fault fakeweather.Fault) fakeweather.City {
+		c := kyiv
+		c.ID, c.Name, c.Fault = 0, name, fault
+		return c
+	}
+	server := httptest.NewServer(fakeweather.NewServer(fakeweather.Config{
+		APIKey: "test-key",
+		Cities: []fakeweather.City{
+			kyiv,
+			city("Brokenville", fakeweather.Fault{Status: http.StatusInternalServerError}),
+			city("Garbleton", fakeweather.Fault{Malformed: true}),
+			city("Slowtown", fakeweather.Fault{Latency: fakeweather.Duration(time.Second)}),
+			city("Quotaville", fakeweather.Fault{Status: http.StatusForbidden, Code: fakeweather.CodeQuotaExceeded}),
+		},
+	}))
+	defer server.Close()
+
+	newService := func(apiKey string) *Service {
+		wp := provider.NewWeatherAPIProvider(server.URL+"/v1", apiKey, server.Client())
+		return NewService(&config.Config{}, wp)
+	}
+
+	tests := []struct {
+		name    string
+		apiKey  string
+		city    string
+		timeout time.Duration
+		wantErr error
+	}{
+		{"found", "test-key", "Kyiv", 0, nil},
+		{"found by coordinates", "test-key", "50.45,30.52", 0, nil},
+		{"blank city", "test-key", " ", 0, ErrInvalidInput},
+		{"unknown city", "test-key", "Atlantis", 0, ErrCityNotFound},
+		{"server error", "test-key", "Brokenville", 0, ErrUpstreamUnavailable},
+		{"malformed JSON", "test-key", "Garbleton", 0, ErrUpstreamUnavailable},
+		{"quota exceeded", "test-key", "Quotaville", 0, ErrQuotaExceeded},
+		{"invalid API key", "wrong-key", "Kyiv", 0, ErrUpstreamUnavailable},
+		{"deadline exceeded", "test-key", "Slowtown", 50 * time.Millisecond, context.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			weather, err := newService(tt.apiKey).FetchWeatherForCity(ctx, tt.city)
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+				require.Equal(t, kyiv.TempC, weather.Temperature)
+				require.Equal(t, kyiv.Condition, weather.Description)
+				return
+			}
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Nil(t, weather)
+			if !errors.Is(tt.wantErr, ErrUpstreamUnavailable) {
+				require.NotErrorIs(t, err, ErrUpstreamUnavailable)
+			}
+		})
+	}
+}
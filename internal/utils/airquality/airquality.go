@@ -0,0 +1,73 @@
+package airquality
+
+import "Weather-API-Application/internal/model"
+
+// US EPA PM2.5 breakpoints (µg/m³, 24-hour average) for index levels 1-5; anything above is level 6.
+var usEPAPM25Breakpoints = []float64{9.0, 35.4, 55.4, 125.4, 225.4}
+
+var usEPACategories = []string{
+	"Good",
+	"Moderate",
+	"Unhealthy for Sensitive Groups",
+	"Unhealthy",
+	"Very Unhealthy",
+	"Hazardous",
+}
+
+// UK DEFRA Daily Air Quality Index PM2.5 bands (µg/m³) for index levels 1-9; anything above is level 10.
+var gbDEFRAPM25Breakpoints = []float64{11, 23, 35, 41, 47, 53, 58, 64, 70}
+
+// USEPAIndexFromPM25 returns the US EPA index level (1-6) for a PM2.5 concentration.
+func USEPAIndexFromPM25(pm25 float64) int {
+	return bandIndex(pm25, usEPAPM25Breakpoints)
+}
+
+// GBDEFRAIndexFromPM25 returns the UK DEFRA index level (1-10) for a PM2.5 concentration.
+func GBDEFRAIndexFromPM25(pm25 float64) int {
+	return bandIndex(pm25, gbDEFRAPM25Breakpoints)
+}
+
+// USEPACategory returns the name of a US EPA index level.
+func USEPACategory(index int) string {
+	if index < 1 || index > len(usEPACategories) {
+		return ""
+	}
+	return usEPACategories[index-1]
+}
+
+// GBDEFRACategory returns the banding of a UK DEFRA index level.
+func GBDEFRACategory(index int) string {
+	switch {
+	case index >= 1 && index <= 3:
+		return "Low"
+	case index >= 4 && index <= 6:
+		return "Moderate"
+	case index >= 7 && index <= 9:
+		return "High"
+	case index == 10:
+		return "Very High"
+	default:
+		return ""
+	}
+}
+
+// Complete fills in indices the provider did not report, derived from PM2.5, and their categories.
+func Complete(aq *model.AirQuality) {
+	if aq.USEPAIndex == 0 {
+		aq.USEPAIndex = USEPAIndexFromPM25(aq.PM25)
+	}
+	if aq.GBDEFRAIndex == 0 {
+		aq.GBDEFRAIndex = GBDEFRAIndexFromPM25(aq.PM25)
+	}
+	aq.USEPACategory = USEPACategory(aq.USEPAIndex)
+	aq.GBDEFRACategory = GBDEFRACategory(aq.GBDEFRAIndex)
+}
+
+func bandIndex(value float64, breakpoints []float64) int {
+	for i, upper := range breakpoints {
+		if value <= upper {
+			return i + 1
+		}
+	}
+	return len(breakpoints) + 1
+}
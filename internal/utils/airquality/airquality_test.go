@@ -0,0 +1,61 @@
+package airquality
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"Weather-API-Application/internal/model"
+)
+
+func TestUSEPAIndexFromPM25(t *testing.T) {
+	tests := []struct {
+		name string
+		pm25 float64
+		want int
+	}{
+		{"clean", 0, 1},
+		{"good upper bound", 9.0, 1},
+		{"moderate", 20, 2},
+		{"sensitive groups", 40, 3},
+		{"unhealthy", 100, 4},
+		{"very unhealthy", 200, 5},
+		{"hazardous", 300, 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := USEPAIndexFromPM25(tt.pm25)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGBDEFRAIndexFromPM25(t *testing.T) {
+	tests := []struct {
+		name string
+		pm25 float64
+		want int
+	}{
+		{"low", 5, 1},
+		{"low upper bound", 35, 3},
+		{"moderate", 45, 5},
+		{"high", 60, 8},
+		{"very high", 80, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GBDEFRAIndexFromPM25(tt.pm25)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCompleteKeepsProviderIndices(t *testing.T) {
+	aq := &model.AirQuality{PM25: 5, USEPAIndex: 3}
+	Complete(aq)
+
+	require.Equal(t, 3, aq.USEPAIndex)
+	require.Equal(t, "Unhealthy for Sensitive Groups", aq.USEPACategory)
+	require.Equal(t, 1, aq.GBDEFRAIndex)
+	require.Equal(t, "Low", aq.GBDEFRACategory)
+}
@@ -0,0 +1,93 @@
+package astronomy
+
+import (
+	"math"
+	"time"
+)
+
+// zenith is the official sunrise/sunset zenith in degrees, accounting for refraction and the solar disc.
+const zenith = 90.833
+
+// synodicMonth is the mean length of a lunar cycle in days.
+const synodicMonth = 29.530588853
+
+// referenceNewMoon is a known new moon used as the start of lunar cycles.
+var referenceNewMoon = time.Date(2000, time.January, 6, 18, 14, 0, 0, time.UTC)
+
+// SunTimes calculates sunrise and sunset in UTC for the given calendar date and coordinates.
+// A nil time means the sun does not rise or set on that day (polar day or night).
+func SunTimes(date time.Time, lat, lon float64) (sunrise, sunset *time.Time) {
+	return sunEvent(date, lat, lon, true), sunEvent(date, lat, lon, false)
+}
+
+// sunEvent implements the sunrise equation from the Almanac for Computers (1990).
+func sunEvent(date time.Time, lat, lon float64, rising bool) *time.Time {
+	dayOfYear := float64(date.YearDay())
+	lngHour := lon / 15
+
+	approx := dayOfYear + (18-lngHour)/24
+	if rising {
+		approx = dayOfYear + (6-lngHour)/24
+	}
+
+	// Sun's mean anomaly and true longitude
+	meanAnomaly := 0.9856*approx - 3.289
+	trueLongitude := normalizeDegrees(meanAnomaly + 1.916*sinDeg(meanAnomaly) + 0.020*sinDeg(2*meanAnomaly) + 282.634)
+
+	// Right ascension, in the same quadrant as the true longitude, converted to hours
+	rightAscension := normalizeDegrees(math.Atan(0.91764*tanDeg(trueLongitude)) * 180 / math.Pi)
+	rightAscension += math.Floor(trueLongitude/90)*90 - math.Floor(rightAscension/90)*90
+	rightAscension /= 15
+
+	// Declination and local hour angle
+	sinDec := 0.39782 * sinDeg(trueLongitude)
+	cosDec := math.Cos(math.Asin(sinDec))
+	cosH := (cosDeg(zenith) - sinDec*sinDeg(lat)) / (cosDec * cosDeg(lat))
+	if cosH > 1 || cosH < -1 {
+		return nil
+	}
+
+	hourAngle := math.Acos(cosH) * 180 / math.Pi
+	if rising {
+		hourAngle = 360 - hourAngle
+	}
+	hourAngle /= 15
+
+	localMeanTime := hourAngle + rightAscension - 0.06571*approx - 6.622
+	utcHours := math.Mod(localMeanTime-lngHour+48, 24)
+
+	t := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC).
+		Add(time.Duration(utcHours * float64(time.Hour))).
+		Truncate(time.Minute)
+	return &t
+}
+
+// MoonPhase returns the name of the moon phase and the illuminated fraction in percent at t.
+func MoonPhase(t time.Time) (string, float64) {
+	age := math.Mod(t.Sub(referenceNewMoon).Hours()/24, synodicMonth)
+	if age < 0 {
+		age += synodicMonth
+	}
+
+	illumination := (1 - math.Cos(2*math.Pi*age/synodicMonth)) / 2 * 100
+
+	phases := []string{
+		"New Moon", "Waxing Crescent", "First Quarter", "Waxing Gibbous",
+		"Full Moon", "Waning Gibbous", "Last Quarter", "Waning Crescent",
+	}
+	// Each phase is centred on its eighth of the cycle
+	index := int(math.Floor(age/synodicMonth*8+0.5)) % len(phases)
+	return phases[index], math.Round(illumination)
+}
+
+func normalizeDegrees(d float64) float64 {
+	d = math.Mod(d, 360)
+	if d < 0 {
+		d += 360
+	}
+	return d
+}
+
+func sinDeg(d float64) float64 { return math.Sin(d * math.Pi / 180) }
+func cosDeg(d float64) float64 { return math.Cos(d * math.Pi / 180) }
+func tanDeg(d float64) float64 { return math.Tan(d * math.Pi / 180) }
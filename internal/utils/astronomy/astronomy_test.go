@@ -0,0 +1,71 @@
+package astronomy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSunTimes(t *testing.T) {
+	tests := []struct {
+		name        string
+		date        time.Time
+		lat, lon    float64
+		wantSunrise time.Time
+		wantSunset  time.Time
+	}{
+		{
+			name:        "Kyiv summer solstice",
+			date:        time.Date(2025, 6, 21, 0, 0, 0, 0, time.UTC),
+			lat:         50.45,
+			lon:         30.52,
+			wantSunrise: time.Date(2025, 6, 21, 1, 47, 0, 0, time.UTC),
+			wantSunset:  time.Date(2025, 6, 21, 18, 12, 0, 0, time.UTC),
+		},
+		{
+			name:        "London winter solstice",
+			date:        time.Date(2025, 12, 21, 0, 0, 0, 0, time.UTC),
+			lat:         51.51,
+			lon:         -0.13,
+			wantSunrise: time.Date(2025, 12, 21, 8, 4, 0, 0, time.UTC),
+			wantSunset:  time.Date(2025, 12, 21, 15, 53, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sunrise, sunset := SunTimes(tt.date, tt.lat, tt.lon)
+			require.NotNil(t, sunrise)
+			require.NotNil(t, sunset)
+			require.WithinDuration(t, tt.wantSunrise, *sunrise, 5*time.Minute)
+			require.WithinDuration(t, tt.wantSunset, *sunset, 5*time.Minute)
+		})
+	}
+}
+
+func TestSunTimesPolarNight(t *testing.T) {
+	sunrise, sunset := SunTimes(time.Date(2025, 12, 21, 0, 0, 0, 0, time.UTC), 78.22, 15.65)
+	require.Nil(t, sunrise)
+	require.Nil(t, sunset)
+}
+
+func TestMoonPhase(t *testing.T) {
+	tests := []struct {
+		name             string
+		at               time.Time
+		wantPhase        string
+		wantIllumination float64
+	}{
+		{"new moon", time.Date(2024, 1, 11, 12, 0, 0, 0, time.UTC), "New Moon", 0},
+		{"full moon", time.Date(2024, 1, 25, 18, 0, 0, 0, time.UTC), "Full Moon", 100},
+		{"first quarter", time.Date(2024, 1, 18, 3, 0, 0, 0, time.UTC), "First Quarter", 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			phase, illumination := MoonPhase(tt.at)
+			require.Equal(t, tt.wantPhase, phase)
+			// The mean lunar cycle drifts from the true one by up to about half a day
+			require.InDelta(t, tt.wantIllumination, illumination, 10)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package fields
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type sample struct {
+	Temperature float64  `json:"temperature"`
+	Humidity    float64  `json:"humidity"`
+	UVIndex     *float64 `json:"uv_index,omitempty"`
+	Cache       string   `json:"-"`
+	hidden      string
+}
+
+func TestNames(t *testing.T) {
+	require.Equal(t, []string{"temperature", "humidity", "uv_index"}, Names(&sample{}))
+}
+
+func TestParse(t *testing.T) {
+	allowed := Names(sample{})
+	tests := []struct {
+		name    string
+		raw     string
+		want    []string
+		wantErr bool
+	}{
+		{"empty", " ", nil, false},
+		{"single", "temperature", []string{"temperature"}, false},
+		{"case and spaces", " Temperature , humidity", []string{"temperature", "humidity"}, false},
+		{"excluded field", "cache", nil, true},
+		{"unknown", "temperature,wind", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.raw, allowed)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSelect(t *testing.T) {
+	got, err := Select(&sample{Temperature: 15.8, Humidity: 52, Cache: "HIT"}, []string{"temperature", "uv_index"})
+	require.NoError(t, err)
+
+	data, err := json.Marshal(got)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"temperature": 15.8}`, string(data))
+}
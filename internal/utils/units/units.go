@@ -0,0 +1,142 @@
+package units
+
+import (
+	"math"
+	"strings"
+
+	"Weather-API-Application/internal/model"
+)
+
+// System is a unit system. Providers report data in metric units
+// (°C, km/h, hPa, mm, km), which are converted at the edge of the application.
+type System string
+
+const (
+	Metric     System = model.UnitsMetric
+	Imperial   System = model.UnitsImperial
+	Scientific System = model.UnitsScientific
+)
+
+var labels = map[System]model.Units{
+	Metric:     {System: model.UnitsMetric, Temperature: "°C", WindSpeed: "km/h", Pressure: "hPa", Precipitation: "mm", Visibility: "km"},
+	Imperial:   {System: model.UnitsImperial, Temperature: "°F", WindSpeed: "mph", Pressure: "inHg", Precipitation: "in", Visibility: "mi"},
+	Scientific: {System: model.UnitsScientific, Temperature: "K", WindSpeed: "m/s", Pressure: "Pa", Precipitation: "mm", Visibility: "m"},
+}
+
+// Parse returns the unit system with the given name. An empty name selects Metric.
+func Parse(name string) (System, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return Metric, true
+	}
+	system := System(name)
+	_, ok := labels[system]
+	return system, ok
+}
+
+// Labels returns the unit names of the system.
+func (s System) Labels() *model.Units {
+	l := labels[s]
+	return &l
+}
+
+// Temperature converts degrees Celsius.
+func (s System) Temperature(celsius float64) float64 {
+	switch s {
+	case Imperial:
+		return round(celsius*9/5 + 32)
+	case Scientific:
+		return round(celsius + 273.15)
+	}
+	return celsius
+}
+
+// WindSpeed converts kilometres per hour.
+func (s System) WindSpeed(kph float64) float64 {
+	switch s {
+	case Imperial:
+		return round(kph / 1.609344)
+	case Scientific:
+		return round(kph / 3.6)
+	}
+	return kph
+}
+
+// Pressure converts hectopascals.
+func (s System) Pressure(hpa float64) float64 {
+	switch s {
+	case Imperial:
+		return round(hpa * 0.0295299830714)
+	case Scientific:
+		return round(hpa * 100)
+	}
+	return hpa
+}
+
+// Precipitation converts millimetres.
+func (s System) Precipitation(mm float64) float64 {
+	if s == Imperial {
+		return round(mm / 25.4)
+	}
+	return mm
+}
+
+// Distance converts kilometres.
+func (s System) Distance(km float64) float64 {
+	switch s {
+	case Imperial:
+		return round(km / 1.609344)
+	case Scientific:
+		return round(km * 1000)
+	}
+	return km
+}
+
+// ConvertWeather converts the weather in place and records the units used.
+func ConvertWeather(w *model.Weather, s System) {
+	w.Temperature = s.Temperature(w.Temperature)
+	w.FeelsLike = s.Temperature(w.FeelsLike)
+	w.WindSpeed = s.WindSpeed(w.WindSpeed)
+	w.WindGust = s.WindSpeed(w.WindGust)
+	w.Pressure = s.Pressure(w.Pressure)
+	w.Precipitation = s.Precipitation(w.Precipitation)
+	w.Visibility = s.Distance(w.Visibility)
+	w.Units = s.Labels()
+}
+
+// ConvertForecast converts the daily forecast in place and records the units used.
+func ConvertForecast(f *model.Forecast, s System) {
+	for i := range f.Days {
+		d := &f.Days[i]
+		d.MinTemperature = s.Temperature(d.MinTemperature)
+		d.MaxTemperature = s.Temperature(d.MaxTemperature)
+		d.MaxWindSpeed = s.WindSpeed(d.MaxWindSpeed)
+	}
+	f.Units = s.Labels()
+}
+
+// ConvertHourlyForecast converts the hourly forecast in place and records the units used.
+func ConvertHourlyForecast(f *model.HourlyForecast, s System) {
+	for i := range f.Hours {
+		h := &f.Hours[i]
+		h.Temperature = s.Temperature(h.Temperature)
+		h.Precipitation = s.Precipitation(h.Precipitation)
+	}
+	f.Units = s.Labels()
+}
+
+// ConvertHistory converts the history in place and records the units used.
+func ConvertHistory(h *model.History, s System) {
+	h.MinTemperature = s.Temperature(h.MinTemperature)
+	h.MaxTemperature = s.Temperature(h.MaxTemperature)
+	h.AvgTemperature = s.Temperature(h.AvgTemperature)
+	for i := range h.Hours {
+		h.Hours[i].Temperature = s.Temperature(h.Hours[i].Temperature)
+	}
+	h.Units = s.Labels()
+}
+
+// round keeps two decimal places so that converted values do not carry float noise.
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
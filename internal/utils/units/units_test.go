@@ -0,0 +1,56 @@
+package units
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		want   System
+		wantOk bool
+	}{
+		{"default", "", Metric, true},
+		{"metric", "metric", Metric, true},
+		{"case and spaces", " Imperial ", Imperial, true},
+		{"scientific", "scientific", Scientific, true},
+		{"unknown", "nautical", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Parse(tt.input)
+			require.Equal(t, tt.wantOk, ok)
+			if ok {
+				require.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestConversions(t *testing.T) {
+	tests := []struct {
+		name          string
+		system        System
+		temperature   float64
+		windSpeed     float64
+		pressure      float64
+		precipitation float64
+		distance      float64
+	}{
+		{"metric", Metric, 20, 36, 1013.25, 25.4, 10},
+		{"imperial", Imperial, 68, 22.37, 29.92, 1, 6.21},
+		{"scientific", Scientific, 293.15, 10, 101325, 25.4, 10000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.InDelta(t, tt.temperature, tt.system.Temperature(20), 0.01)
+			require.InDelta(t, tt.windSpeed, tt.system.WindSpeed(36), 0.01)
+			require.InDelta(t, tt.pressure, tt.system.Pressure(1013.25), 0.01)
+			require.InDelta(t, tt.precipitation, tt.system.Precipitation(25.4), 0.01)
+			require.InDelta(t, tt.distance, tt.system.Distance(10), 0.01)
+		})
+	}
+}
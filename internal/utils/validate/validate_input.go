@@ -3,6 +3,23 @@ package validate
 import (
 	"regexp"
 	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// IncludeAirQuality is the include value requesting air quality data.
+const IncludeAirQuality = "aqi"
+
+const (
+	// MaxForecastDays is the longest forecast that can be requested.
+	MaxForecastDays = 14
+	// MaxHourlyWindow is the longest time window of an hourly forecast.
+	MaxHourlyWindow = 48 * time.Hour
+	// MaxAstronomyOffset is how far from today astronomy data can be requested.
+	MaxAstronomyOffset = 365 * 24 * time.Hour
+	// MinSearchQueryLength and MaxSearchQueryLength bound the city search query, in characters.
+	MinSearchQueryLength = 2
+	MaxSearchQueryLength = 100
 )
 
 func IsValidEmail(email string) bool {
@@ -14,7 +31,54 @@ func IsValidCity(city string) bool {
 	return strings.TrimSpace(city) != ""
 }
 
+func IsValidSearchQuery(query string) bool {
+	n := utf8.RuneCountInString(strings.TrimSpace(query))
+	return n >= MinSearchQueryLength && n <= MaxSearchQueryLength
+}
+
+func IsValidLatitude(lat float64) bool {
+	return lat >= -90 && lat <= 90
+}
+
+func IsValidLongitude(lon float64) bool {
+	return lon >= -180 && lon <= 180
+}
+
 func IsValidFrequency(frequency string) bool {
 	freq := strings.ToLower(strings.TrimSpace(frequency))
-	return freq == "hourly" || freq == "daily"
+	return freq == "hourly" || freq == "daily" || freq == "alerts"
+}
+
+func IsValidForecastDays(days int) bool {
+	return days >= 1 && days <= MaxForecastDays
+}
+
+func IsValidHourlyWindow(from, to time.Time) bool {
+	return to.After(from) && to.Sub(from) <= MaxHourlyWindow
+}
+
+// IsValidHistoryDate reports whether date lies before the current UTC day.
+func IsValidHistoryDate(date, now time.Time) bool {
+	today := now.UTC().Truncate(24 * time.Hour)
+	return date.Before(today)
+}
+
+// IsValidAstronomyDate reports whether date is within MaxAstronomyOffset of the current UTC day.
+func IsValidAstronomyDate(date, now time.Time) bool {
+	today := now.UTC().Truncate(24 * time.Hour)
+	offset := date.Sub(today)
+	return offset >= -MaxAstronomyOffset && offset <= MaxAstronomyOffset
+}
+
+// IsValidInclude checks that every comma-separated value of the include parameter is supported.
+func IsValidInclude(include string) bool {
+	if strings.TrimSpace(include) == "" {
+		return true
+	}
+	for _, v := range strings.Split(include, ",") {
+		if strings.ToLower(strings.TrimSpace(v)) != IncludeAirQuality {
+			return false
+		}
+	}
+	return true
 }
@@ -2,7 +2,9 @@ package validate
 
 import (
 	"github.com/stretchr/testify/require"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestIsValidCity(t *testing.T) {
@@ -22,6 +24,47 @@ func TestIsValidCity(t *testing.T) {
 	}
 }
 
+func TestIsValidSearchQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  bool
+	}{
+		{"empty", "", false},
+		{"single letter", " K ", false},
+		{"prefix", "Ky", true},
+		{"cyrillic", "Київ", true},
+		{"too long", strings.Repeat("a", MaxSearchQueryLength+1), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := IsValidSearchQuery(tt.query)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIsValidCoordinates(t *testing.T) {
+	tests := []struct {
+		name    string
+		lat     float64
+		lon     float64
+		wantLat bool
+		wantLon bool
+	}{
+		{"Kyiv", 50.45, 30.52, true, true},
+		{"boundaries", -90, 180, true, true},
+		{"latitude out of range", 90.1, 0, false, true},
+		{"longitude out of range", 0, -180.5, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.wantLat, IsValidLatitude(tt.lat))
+			require.Equal(t, tt.wantLon, IsValidLongitude(tt.lon))
+		})
+	}
+}
+
 func TestIsValidEmail(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -53,6 +96,7 @@ func TestIsValidFrequency(t *testing.T) {
 		{"valid", "Hourly", true},
 		{"valid", "DAILY", true},
 		{"plus", "hourly", true},
+		{"alerts", "alerts", true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -61,3 +105,105 @@ func TestIsValidFrequency(t *testing.T) {
 		})
 	}
 }
+
+func TestIsValidForecastDays(t *testing.T) {
+	tests := []struct {
+		name string
+		days int
+		want bool
+	}{
+		{"zero", 0, false},
+		{"negative", -1, false},
+		{"one", 1, true},
+		{"max", MaxForecastDays, true},
+		{"too many", MaxForecastDays + 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := IsValidForecastDays(tt.days)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIsValidHourlyWindow(t *testing.T) {
+	from := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		to   time.Time
+		want bool
+	}{
+		{"to before from", from.Add(-time.Hour), false},
+		{"empty window", from, false},
+		{"12 hours", from.Add(12 * time.Hour), true},
+		{"max", from.Add(MaxHourlyWindow), true},
+		{"too long", from.Add(MaxHourlyWindow + time.Hour), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := IsValidHourlyWindow(from, tt.to)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIsValidHistoryDate(t *testing.T) {
+	now := time.Date(2025, 6, 10, 15, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		date time.Time
+		want bool
+	}{
+		{"yesterday", time.Date(2025, 6, 9, 0, 0, 0, 0, time.UTC), true},
+		{"last week", time.Date(2025, 6, 3, 0, 0, 0, 0, time.UTC), true},
+		{"today", time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC), false},
+		{"future", time.Date(2025, 6, 11, 0, 0, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := IsValidHistoryDate(tt.date, now)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIsValidAstronomyDate(t *testing.T) {
+	now := time.Date(2025, 6, 10, 15, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		date time.Time
+		want bool
+	}{
+		{"today", time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC), true},
+		{"last month", time.Date(2025, 5, 10, 0, 0, 0, 0, time.UTC), true},
+		{"next month", time.Date(2025, 7, 10, 0, 0, 0, 0, time.UTC), true},
+		{"too far back", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), false},
+		{"too far ahead", time.Date(2026, 6, 20, 0, 0, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := IsValidAstronomyDate(tt.date, now)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIsValidInclude(t *testing.T) {
+	tests := []struct {
+		name    string
+		include string
+		want    bool
+	}{
+		{"empty", "", true},
+		{"aqi", "aqi", true},
+		{"case and spaces", " AQI ", true},
+		{"unknown", "pollen", false},
+		{"mixed", "aqi,pollen", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := IsValidInclude(tt.include)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS weather_observations (
+    id BIGSERIAL PRIMARY KEY,
+    city TEXT NOT NULL,
+    observed_at TIMESTAMPTZ NOT NULL,
+    temperature DOUBLE PRECISION NOT NULL,
+    humidity DOUBLE PRECISION NOT NULL,
+    description TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_weather_observations_city_observed_at ON weather_observations (city, observed_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS weather_observations;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE weather_subscriptions
+    ADD COLUMN IF NOT EXISTS include_air_quality BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE weather_subscriptions
+    DROP COLUMN IF EXISTS include_air_quality;
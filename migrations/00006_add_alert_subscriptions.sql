@@ -0,0 +1,21 @@
+-- +goose Up
+ALTER TABLE weather_subscriptions
+    DROP CONSTRAINT IF EXISTS weather_subscriptions_frequency_check;
+ALTER TABLE weather_subscriptions
+    ADD CONSTRAINT weather_subscriptions_frequency_check CHECK (frequency IN ('daily', 'hourly', 'alerts'));
+
+CREATE TABLE IF NOT EXISTS sent_alerts (
+    subscription_id INTEGER NOT NULL REFERENCES weather_subscriptions (id) ON DELETE CASCADE,
+    alert_id TEXT NOT NULL,
+    sent_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (subscription_id, alert_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS sent_alerts;
+
+DELETE FROM weather_subscriptions WHERE frequency = 'alerts';
+ALTER TABLE weather_subscriptions
+    DROP CONSTRAINT IF EXISTS weather_subscriptions_frequency_check;
+ALTER TABLE weather_subscriptions
+    ADD CONSTRAINT weather_subscriptions_frequency_check CHECK (frequency IN ('daily', 'hourly'));
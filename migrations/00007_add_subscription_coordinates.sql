@@ -0,0 +1,14 @@
+-- +goose Up
+ALTER TABLE weather_subscriptions
+    ADD COLUMN IF NOT EXISTS latitude  DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION,
+    ADD CONSTRAINT weather_subscriptions_coordinates_check CHECK (
+        (latitude IS NULL AND longitude IS NULL)
+        OR (latitude BETWEEN -90 AND 90 AND longitude BETWEEN -180 AND 180)
+    );
+
+-- +goose Down
+ALTER TABLE weather_subscriptions
+    DROP CONSTRAINT IF EXISTS weather_subscriptions_coordinates_check,
+    DROP COLUMN IF EXISTS longitude,
+    DROP COLUMN IF EXISTS latitude;
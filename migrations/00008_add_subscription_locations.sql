@@ -0,0 +1,29 @@
+-- +goose Up
+ALTER TABLE weather_subscriptions
+    ADD COLUMN IF NOT EXISTS location_id       TEXT,
+    ADD COLUMN IF NOT EXISTS location_name     TEXT,
+    ADD COLUMN IF NOT EXISTS location_country  TEXT,
+    ADD COLUMN IF NOT EXISTS location_timezone TEXT;
+
+-- Resolved subscriptions are unique per canonical location, so "kyiv", "Kyiv" and "Kiev"
+-- map to one row. Rows that are not resolved yet keep the old email/city uniqueness.
+ALTER TABLE weather_subscriptions
+    DROP CONSTRAINT IF EXISTS weather_subscriptions_email_city_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscriptions_email_location
+    ON weather_subscriptions (email, location_id)
+    WHERE location_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscriptions_email_city_unresolved
+    ON weather_subscriptions (email, city)
+    WHERE location_id IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_subscriptions_email_city_unresolved;
+DROP INDEX IF EXISTS idx_subscriptions_email_location;
+ALTER TABLE weather_subscriptions
+    ADD CONSTRAINT weather_subscriptions_email_city_key UNIQUE (email, city);
+
+ALTER TABLE weather_subscriptions
+    DROP COLUMN IF EXISTS location_timezone,
+    DROP COLUMN IF EXISTS location_country,
+    DROP COLUMN IF EXISTS location_name,
+    DROP COLUMN IF EXISTS location_id;
@@ -0,0 +1,8 @@
+-- +goose Up
+ALTER TABLE weather_subscriptions
+    ADD COLUMN IF NOT EXISTS units TEXT NOT NULL DEFAULT 'metric'
+        CHECK (units IN ('metric', 'imperial', 'scientific'));
+
+-- +goose Down
+ALTER TABLE weather_subscriptions
+    DROP COLUMN IF EXISTS units;
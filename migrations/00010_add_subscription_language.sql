@@ -0,0 +1,8 @@
+-- +goose Up
+ALTER TABLE weather_subscriptions
+    ADD COLUMN IF NOT EXISTS language TEXT NOT NULL DEFAULT 'en'
+        CHECK (language IN ('en', 'uk'));
+
+-- +goose Down
+ALTER TABLE weather_subscriptions
+    DROP COLUMN IF EXISTS language;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- Outbound calls per weather provider and period: a day ("2006-01-02") or a month ("2006-01").
+CREATE TABLE IF NOT EXISTS provider_usage (
+    provider TEXT NOT NULL,
+    period TEXT NOT NULL,
+    calls BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (provider, period)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS provider_usage;
@@ -0,0 +1,33 @@
+-- +goose Up
+ALTER TABLE weather_subscriptions
+    ADD COLUMN IF NOT EXISTS next_run_at  TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS last_sent_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_next_run_at
+    ON weather_subscriptions (next_run_at)
+    WHERE confirmed;
+
+-- One row per scheduled update of a subscription. The slot is the next_run_at the update was
+-- claimed for, so each slot is claimed once; pending rows whose claim expired are retried.
+CREATE TABLE IF NOT EXISTS subscription_deliveries (
+    subscription_id INTEGER NOT NULL REFERENCES weather_subscriptions (id) ON DELETE CASCADE,
+    slot TIMESTAMPTZ NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 1,
+    claimed_at TIMESTAMPTZ NOT NULL,
+    sent_at TIMESTAMPTZ,
+    error TEXT,
+    PRIMARY KEY (subscription_id, slot)
+);
+
+CREATE INDEX IF NOT EXISTS idx_deliveries_pending
+    ON subscription_deliveries (claimed_at)
+    WHERE status = 'pending';
+
+-- +goose Down
+DROP TABLE IF EXISTS subscription_deliveries;
+DROP INDEX IF EXISTS idx_subscriptions_next_run_at;
+
+ALTER TABLE weather_subscriptions
+    DROP COLUMN IF EXISTS last_sent_at,
+    DROP COLUMN IF EXISTS next_run_at;
@@ -0,0 +1,9 @@
+-- +goose Up
+-- Failed attempts to resolve the location of an unresolved subscription. The background
+-- backfill skips subscriptions that failed too often, so a bad row is not retried forever.
+ALTER TABLE weather_subscriptions
+    ADD COLUMN IF NOT EXISTS location_attempts INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE weather_subscriptions
+    DROP COLUMN IF EXISTS location_attempts;
@@ -0,0 +1,23 @@
+-- +goose Up
+-- Deliveries are marked 'sending' right before their email is sent, so that only one claim of a
+-- slot sends it. Like pending ones, sending deliveries are claimed again once their lease expires.
+ALTER TABLE subscription_deliveries
+    DROP CONSTRAINT IF EXISTS subscription_deliveries_status_check,
+    ADD CONSTRAINT subscription_deliveries_status_check
+        CHECK (status IN ('pending', 'sending', 'sent', 'failed'));
+
+CREATE INDEX IF NOT EXISTS idx_deliveries_sending
+    ON subscription_deliveries (claimed_at)
+    WHERE status = 'sending';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_deliveries_sending;
+
+UPDATE subscription_deliveries
+SET status = 'pending'
+WHERE status = 'sending';
+
+ALTER TABLE subscription_deliveries
+    DROP CONSTRAINT IF EXISTS subscription_deliveries_status_check,
+    ADD CONSTRAINT subscription_deliveries_status_check
+        CHECK (status IN ('pending', 'sent', 'failed'));
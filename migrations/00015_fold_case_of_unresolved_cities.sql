@@ -0,0 +1,22 @@
+-- +goose Up
+-- Unresolved subscriptions are matched by city case-insensitively, so "kyiv" and "Kyiv" must be
+-- one row. Case-only duplicates left by the old (email, city) index are removed first, keeping
+-- the confirmed one, then the oldest.
+DELETE FROM weather_subscriptions s
+USING weather_subscriptions keep
+WHERE s.location_id IS NULL
+  AND keep.location_id IS NULL
+  AND s.email = keep.email
+  AND lower(s.city) = lower(keep.city)
+  AND (COALESCE(keep.confirmed, FALSE), -keep.id) > (COALESCE(s.confirmed, FALSE), -s.id);
+
+DROP INDEX IF EXISTS idx_subscriptions_email_city_unresolved;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscriptions_email_city_unresolved
+    ON weather_subscriptions (email, lower(city))
+    WHERE location_id IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_subscriptions_email_city_unresolved;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscriptions_email_city_unresolved
+    ON weather_subscriptions (email, city)
+    WHERE location_id IS NULL;